@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jawher/mow.cli/internal/fsm"
+	"github.com/jawher/mow.cli/internal/matcher"
+)
+
+// subCommand returns the sub command having arg as an alias
+func (c *Cmd) subCommand(arg string) *Cmd {
+	for _, sub := range c.commands {
+		if sub.isAlias(arg) {
+			return sub
+		}
+	}
+	return nil
+}
+
+// abbreviatedSubCommand is like subCommand, but if abbreviations are allowed,
+// also returns the only sub command having an alias starting with arg
+func (c *Cmd) abbreviatedSubCommand(arg string) *Cmd {
+	if sub := c.subCommand(arg); sub != nil || !c.abbreviations {
+		return sub
+	}
+
+	var res *Cmd
+	for _, sub := range c.commands {
+		if !sub.hasAliasPrefix(arg) {
+			continue
+		}
+		if res != nil {
+			// ambiguous
+			return nil
+		}
+		res = sub
+	}
+	return res
+}
+
+// abbreviatedCommandPos returns the position in args of the arg rejected by the fsm with err
+// if the args before it form a valid usage and it is the abbreviation of a sub command, -1 otherwise
+func (c *Cmd) abbreviatedCommandPos(err error, args []string) int {
+	e, ok := err.(*fsm.ParseError)
+	if !ok || !e.Terminal || e.Pos >= len(args) || optionsEnded(args[:e.Pos]) {
+		return -1
+	}
+	if c.abbreviatedSubCommand(args[e.Pos]) == nil {
+		return -1
+	}
+	return e.Pos
+}
+
+func (c *Cmd) hasAliasPrefix(prefix string) bool {
+	if prefix == "" || strings.HasPrefix(prefix, "-") {
+		return false
+	}
+	for _, alias := range c.aliases {
+		if strings.HasPrefix(alias, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// abbreviationCandidates returns the long option names or the sub command aliases starting with arg
+// when arg is an ambiguous abbreviation, nil otherwise
+func (c *Cmd) abbreviationCandidates(arg string) []string {
+	if !c.abbreviations {
+		return nil
+	}
+
+	var (
+		res  []string
+		seen = map[interface{}]bool{}
+	)
+	if strings.HasPrefix(arg, "--") {
+		name := strings.SplitN(arg, "=", 2)[0]
+		if _, opt := matcher.LookupLongOpt(c.optionsIdx, name, c.abbreviations); opt != nil {
+			return nil
+		}
+		for n, opt := range c.optionsIdx {
+			if strings.HasPrefix(n, name) && strings.HasPrefix(n, "--") {
+				res = append(res, n)
+				seen[opt] = true
+			}
+		}
+	} else {
+		if c.abbreviatedSubCommand(arg) != nil {
+			return nil
+		}
+		for _, sub := range c.commands {
+			if !sub.hasAliasPrefix(arg) {
+				continue
+			}
+			for _, alias := range sub.aliases {
+				if strings.HasPrefix(alias, arg) {
+					res = append(res, alias)
+				}
+			}
+			seen[sub] = true
+		}
+	}
+
+	if len(seen) < 2 {
+		return nil
+	}
+	sort.Strings(res)
+	return res
+}
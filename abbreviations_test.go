@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbbreviations(t *testing.T) {
+	cases := []struct {
+		args    []string
+		verbose bool
+		color   bool
+		name    string
+		command string
+	}{
+		{args: []string{"app", "--verb"}, verbose: true, color: true},
+		{args: []string{"app", "--verbose", "--na", "x"}, verbose: true, color: true, name: "x"},
+		{args: []string{"app", "--na=x"}, color: true, name: "x"},
+		{args: []string{"app", "--no-c"}, color: false},
+		{args: []string{"app", "--col=false"}, color: false},
+		{args: []string{"app", "rem"}, color: true, command: "remote"},
+		{args: []string{"app", "--verb", "rem", "--fo"}, verbose: true, color: true, command: "remote force"},
+		{args: []string{"app", "rev"}, color: true, command: "revert"},
+		{args: []string{"app", "--name", "rem"}, color: true, name: "rem"},
+		{args: []string{"app", "--name", "rem", "rem"}, color: true, name: "rem", command: "remote"},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			defer exitShouldNotCalled(t)()
+
+			app := App("app", "")
+			app.AllowAbbreviations = true
+			verbose := app.BoolOpt("verbose", false, "")
+			app.BoolOpt("version", false, "")
+			color := app.Bool(BoolOpt{Name: "color", Value: true, Negatable: true})
+			name := app.StringOpt("name", "", "")
+
+			command := ""
+			app.Command("remote", "", func(cmd *Cmd) {
+				force := cmd.BoolOpt("force", false, "")
+				cmd.Action = func() {
+					command = "remote"
+					if *force {
+						command += " force"
+					}
+				}
+			})
+			app.Command("revert", "", func(cmd *Cmd) {
+				cmd.Action = func() { command = "revert" }
+			})
+			app.Action = func() {}
+
+			require.NoError(t, app.Run(cas.args))
+			require.Equal(t, cas.verbose, *verbose)
+			require.Equal(t, cas.color, *color)
+			require.Equal(t, cas.name, *name)
+			require.Equal(t, cas.command, command)
+		})
+	}
+}
+
+func TestAbbreviationsOptionValueIsNotACommand(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	app := App("app", "")
+	app.AllowAbbreviations = true
+	env := app.StringOpt("env", "", "")
+
+	called := false
+	app.Command("production", "", func(cmd *Cmd) {
+		cmd.Action = func() { called = true }
+	})
+
+	require.NoError(t, app.Run([]string{"app", "--env", "prod", "production"}))
+	require.Equal(t, "prod", *env)
+	require.True(t, called)
+}
+
+func TestAbbreviationsAmbiguous(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected error
+		message  string
+	}{
+		{
+			args:     []string{"app", "--ver"},
+			expected: &AmbiguousAbbreviationError{Command: "app", Index: 1, Abbreviation: "--ver", Candidates: []string{"--verbose", "--version"}},
+			message:  "ambiguous abbreviation --ver, could be --verbose or --version",
+		},
+		{
+			args:     []string{"app", "--ver=true"},
+			expected: &AmbiguousAbbreviationError{Command: "app", Index: 1, Abbreviation: "--ver", Candidates: []string{"--verbose", "--version"}},
+			message:  "ambiguous abbreviation --ver, could be --verbose or --version",
+		},
+		{
+			args:     []string{"app", "re"},
+			expected: &AmbiguousAbbreviationError{Command: "app", Index: 1, Abbreviation: "re", Candidates: []string{"remote", "revert"}},
+			message:  "ambiguous abbreviation re, could be remote or revert",
+		},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			defer suppressOutput()()
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+			app.AllowAbbreviations = true
+			app.BoolOpt("verbose", false, "")
+			app.BoolOpt("version", false, "")
+			app.Command("remote", "", func(cmd *Cmd) { cmd.Action = func() {} })
+			app.Command("revert", "", func(cmd *Cmd) { cmd.Action = func() {} })
+			app.Action = func() {}
+
+			err := app.Run(cas.args)
+			require.Equal(t, cas.expected, err)
+			require.EqualError(t, err, cas.message)
+
+			var ambiguous *AmbiguousAbbreviationError
+			require.True(t, errors.As(err, &ambiguous))
+		})
+	}
+}
+
+func TestAbbreviationsDisabledByDefault(t *testing.T) {
+	defer suppressOutput()()
+
+	app := App("app", "")
+	app.ErrorHandling = flag.ContinueOnError
+	app.BoolOpt("verbose", false, "")
+	app.Command("remote", "", func(cmd *Cmd) { cmd.Action = func() {} })
+	app.Action = func() {}
+
+	require.Equal(t, &UnknownOptionError{Command: "app", Index: 1, Option: "--verb"}, app.Run([]string{"app", "--verb"}))
+
+	var unexpected *UnexpectedArgumentError
+	require.True(t, errors.As(app.Run([]string{"app", "rem"}), &unexpected))
+	require.Equal(t, "rem", unexpected.Arg)
+}
+
+func TestAbbreviationsPreferExactNames(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	app := App("app", "")
+	app.AllowAbbreviations = true
+	all := app.BoolOpt("all", false, "")
+	allFiles := app.BoolOpt("all-files", false, "")
+	app.Action = func() {}
+
+	require.NoError(t, app.Run([]string{"app", "--all"}))
+	require.True(t, *all)
+	require.False(t, *allFiles)
+}
+
+func TestAbbreviationsHelp(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"app", "stat", "--help"}, expected: "Usage: app status"},
+		{args: []string{"app", "stat", "-h"}, expected: "Usage: app status"},
+		{args: []string{"app", "--verbose", "stat", "--help"}, expected: "Usage: app status"},
+		{args: []string{"app", "rem", "up", "--help"}, expected: "Usage: app remote update"},
+		{args: []string{"app", "--name", "stat", "--help"}, expected: "Usage: app [OPTIONS] COMMAND [arg...]"},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			var stdErr string
+			defer captureAndRestoreOutput(nil, &stdErr)()
+
+			exitCalled := false
+			defer exitShouldBeCalledWith(t, 0, &exitCalled)()
+
+			app := App("app", "")
+			app.AllowAbbreviations = true
+			app.BoolOpt("verbose", false, "")
+			app.StringOpt("name", "", "")
+			app.Command("status", "", func(cmd *Cmd) { cmd.Action = func() {} })
+			app.Command("remote", "", func(cmd *Cmd) {
+				cmd.Command("update", "", func(cmd *Cmd) { cmd.Action = func() {} })
+			})
+
+			require.NoError(t, app.Run(cas.args))
+			require.True(t, exitCalled)
+			require.Contains(t, stdErr, "\n"+cas.expected+"\n")
+		})
+	}
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/jawher/mow.cli/internal/lexer"
 
@@ -24,6 +25,10 @@ type BoolArg struct {
 	HideValue bool
 	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(bool) error
 }
 
 func (a BoolArg) value(into *bool) (flag.Value, *bool) {
@@ -47,6 +52,15 @@ type StringArg struct {
 	HideValue bool
 	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// The values this argument accepts, any other value is rejected. Also used as the completion candidates if Complete is not set
+	Choices []string
+	// Set to true if this argument holds a secret, e.g. a password: its value is never shown in the help messages nor in the config dumps,
+	// and if one of its env vars VAR is not set, the value is read from the file named by VAR_FILE, with the trailing newlines stripped
+	Secret bool
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(string) error
 }
 
 func (a StringArg) value(into *string) (flag.Value, *string) {
@@ -70,6 +84,10 @@ type IntArg struct {
 	HideValue bool
 	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(int) error
 }
 
 func (a IntArg) value(into *int) (flag.Value, *int) {
@@ -93,6 +111,10 @@ type Float64Arg struct {
 	HideValue bool
 	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(float64) error
 }
 
 func (a Float64Arg) value(into *float64) (flag.Value, *float64) {
@@ -117,6 +139,12 @@ type StringsArg struct {
 	HideValue bool
 	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// The values this argument accepts, any other value is rejected. Also used as the completion candidates if Complete is not set
+	Choices []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func([]string) error
 }
 
 func (a StringsArg) value(into *[]string) (flag.Value, *[]string) {
@@ -141,6 +169,10 @@ type IntsArg struct {
 	HideValue bool
 	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func([]int) error
 }
 
 func (a IntsArg) value(into *[]int) (flag.Value, *[]int) {
@@ -165,6 +197,10 @@ type Floats64Arg struct {
 	HideValue bool
 	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func([]float64) error
 }
 
 func (a Floats64Arg) value(into *[]float64) (flag.Value, *[]float64) {
@@ -174,6 +210,176 @@ func (a Floats64Arg) value(into *[]float64) (flag.Value, *[]float64) {
 	return values.NewFloats64(into, a.Value), into
 }
 
+// DurationArg describes a duration argument
+type DurationArg struct {
+	// The argument name as will be shown in help messages
+	Name string
+	// The argument description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this argument
+	EnvVar string
+	// The argument's initial value
+	Value time.Duration
+	// A boolean to display or not the current value of the argument in the help message
+	HideValue bool
+	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(time.Duration) error
+}
+
+func (a DurationArg) value(into *time.Duration) (flag.Value, *time.Duration) {
+	if into == nil {
+		into = new(time.Duration)
+	}
+	return values.NewDuration(into, a.Value), into
+}
+
+// DurationsArg describes a duration slice argument
+type DurationsArg struct {
+	// The argument name as will be shown in help messages
+	Name string
+	// The argument description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this argument.
+	// The env variable should contain a comma separated list of values
+	EnvVar string
+	// The argument's initial value
+	Value []time.Duration
+	// A boolean to display or not the current value of the argument in the help message
+	HideValue bool
+	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func([]time.Duration) error
+}
+
+func (a DurationsArg) value(into *[]time.Duration) (flag.Value, *[]time.Duration) {
+	if into == nil {
+		into = new([]time.Duration)
+	}
+	return values.NewDurations(into, a.Value), into
+}
+
+// TimeArg describes a time argument
+type TimeArg struct {
+	// The argument name as will be shown in help messages
+	Name string
+	// The argument description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this argument
+	EnvVar string
+	// The argument's initial value
+	Value time.Time
+	// The layout used to parse and format the argument values, as accepted by time.Parse (time.RFC3339 if empty)
+	Layout string
+	// A boolean to display or not the current value of the argument in the help message
+	HideValue bool
+	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(time.Time) error
+}
+
+func (a TimeArg) value(into *time.Time) (flag.Value, *time.Time) {
+	if into == nil {
+		into = new(time.Time)
+	}
+	return values.NewTime(into, a.Value, a.Layout), into
+}
+
+// TimesArg describes a time slice argument
+type TimesArg struct {
+	// The argument name as will be shown in help messages
+	Name string
+	// The argument description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this argument.
+	// The env variable should contain a comma separated list of values
+	EnvVar string
+	// The argument's initial value
+	Value []time.Time
+	// The layout used to parse and format the argument values, as accepted by time.Parse (time.RFC3339 if empty)
+	Layout string
+	// A boolean to display or not the current value of the argument in the help message
+	HideValue bool
+	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func([]time.Time) error
+}
+
+func (a TimesArg) value(into *[]time.Time) (flag.Value, *[]time.Time) {
+	if into == nil {
+		into = new([]time.Time)
+	}
+	return values.NewTimes(into, a.Value, a.Layout), into
+}
+
+// StringMapArg describes a string map argument
+type StringMapArg struct {
+	// The argument name as will be shown in help messages
+	Name string
+	// The argument description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this argument.
+	// The env variable should contain a comma separated list of key=value pairs
+	EnvVar string
+	// The argument's initial value
+	Value map[string]string
+	// A boolean to display or not the current value of the argument in the help message
+	HideValue bool
+	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(map[string]string) error
+}
+
+func (a StringMapArg) value(into *map[string]string) (flag.Value, *map[string]string) {
+	if into == nil {
+		into = new(map[string]string)
+	}
+	return values.NewStringMap(into, a.Value), into
+}
+
+// IntMapArg describes an int map argument
+type IntMapArg struct {
+	// The argument name as will be shown in help messages
+	Name string
+	// The argument description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this argument.
+	// The env variable should contain a comma separated list of key=value pairs
+	EnvVar string
+	// The argument's initial value
+	Value map[string]int
+	// A boolean to display or not the current value of the argument in the help message
+	HideValue bool
+	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(map[string]int) error
+}
+
+func (a IntMapArg) value(into *map[string]int) (flag.Value, *map[string]int) {
+	if into == nil {
+		into = new(map[string]int)
+	}
+	return values.NewIntMap(into, a.Value), into
+}
+
 // VarArg describes an argument where the type and format of the value is controlled by the developer
 type VarArg struct {
 	// A space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
@@ -189,6 +395,10 @@ type VarArg struct {
 	HideValue bool
 	// Set to true if this arg was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// A function validating the argument's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(flag.Value) error
 }
 
 func (a VarArg) value() flag.Value {
@@ -377,6 +587,170 @@ func (c *Cmd) Floats64ArgPtr(into *[]float64, name string, value []float64, desc
 	})
 }
 
+/*
+DurationArg defines a duration argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The result should be stored in a variable (a pointer to a time.Duration) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) DurationArg(name string, value time.Duration, desc string) *time.Duration {
+	return c.Duration(DurationArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+DurationArgPtr defines a duration argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The into parameter points to a variable (a pointer to a time.Duration) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) DurationArgPtr(into *time.Duration, name string, value time.Duration, desc string) {
+	c.DurationPtr(into, DurationArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+DurationsArg defines a duration slice argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The result should be stored in a variable (a pointer to a time.Duration slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) DurationsArg(name string, value []time.Duration, desc string) *[]time.Duration {
+	return c.Durations(DurationsArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+DurationsArgPtr defines a duration slice argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The into parameter points to a variable (a pointer to a time.Duration slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) DurationsArgPtr(into *[]time.Duration, name string, value []time.Duration, desc string) {
+	c.DurationsPtr(into, DurationsArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+TimeArg defines a time argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The values are parsed and formatted using the time.RFC3339 layout. Use the TimeArg struct to specify another layout.
+
+The result should be stored in a variable (a pointer to a time.Time) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) TimeArg(name string, value time.Time, desc string) *time.Time {
+	return c.Time(TimeArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+TimeArgPtr defines a time argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The values are parsed and formatted using the time.RFC3339 layout. Use the TimeArg struct to specify another layout.
+
+The into parameter points to a variable (a pointer to a time.Time) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) TimeArgPtr(into *time.Time, name string, value time.Time, desc string) {
+	c.TimePtr(into, TimeArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+TimesArg defines a time slice argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The values are parsed and formatted using the time.RFC3339 layout. Use the TimesArg struct to specify another layout.
+
+The result should be stored in a variable (a pointer to a time.Time slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) TimesArg(name string, value []time.Time, desc string) *[]time.Time {
+	return c.Times(TimesArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+TimesArgPtr defines a time slice argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The values are parsed and formatted using the time.RFC3339 layout. Use the TimesArg struct to specify another layout.
+
+The into parameter points to a variable (a pointer to a time.Time slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) TimesArgPtr(into *[]time.Time, name string, value []time.Time, desc string) {
+	c.TimesPtr(into, TimesArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+StringMapArg defines a string map argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The result should be stored in a variable (a pointer to a string map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) StringMapArg(name string, value map[string]string, desc string) *map[string]string {
+	return c.StringMap(StringMapArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+StringMapArgPtr defines a string map argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The into parameter points to a variable (a pointer to a string map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) StringMapArgPtr(into *map[string]string, name string, value map[string]string, desc string) {
+	c.StringMapPtr(into, StringMapArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+IntMapArg defines an int map argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The result should be stored in a variable (a pointer to an int map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) IntMapArg(name string, value map[string]int, desc string) *map[string]int {
+	return c.IntMap(IntMapArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+IntMapArgPtr defines an int map argument on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The into parameter points to a variable (a pointer to an int map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) IntMapArgPtr(into *map[string]int, name string, value map[string]int, desc string) {
+	c.IntMapPtr(into, IntMapArg{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
 /*
 VarArg defines an argument where the type and format is controlled by the developer on the command c named `name` and a description of `desc` which will be used in help messages.
 
@@ -396,7 +770,17 @@ func (c *Cmd) mkArg(arg container.Container) {
 
 	arg.DefaultValue = values.DefaultValue(arg.Value)
 
-	arg.ValueSetFromEnv = values.SetFromEnv(arg.Value, arg.EnvVar)
+	if arg.Secret {
+		arg.HideValue = true
+		arg.ValueEnvVar = values.SetFromSecretEnv(arg.Value, arg.EnvVar, arg.Check)
+	} else {
+		arg.ValueEnvVar = values.SetFromEnv(arg.Value, arg.EnvVar, arg.Check)
+	}
+	arg.ValueSetFromEnv = arg.ValueEnvVar != ""
+	c.setFromConfig(&arg)
+	if arg.Complete == nil && len(arg.Choices) > 0 {
+		arg.Complete = completeChoices(arg.Choices)
+	}
 
 	c.args = append(c.args, &arg)
 	c.argsIdx[arg.Name] = &arg
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringOptWithChoices(t *testing.T) {
+	cases := []struct {
+		args             []string
+		env              map[string]string
+		expectedOptValue string
+	}{
+		{[]string{"app"}, nil, "json"},
+		{[]string{"app", "-f", "yaml"}, nil, "yaml"},
+		{[]string{"app", "--format=table"}, nil, "table"},
+		{[]string{"app"}, map[string]string{"FORMAT": "table"}, "table"},
+		{[]string{"app"}, map[string]string{"FORMAT": "xml"}, "json"},
+	}
+
+	for _, cas := range cases {
+		restore := setAndRestoreEnv(cas.env)
+		runAppAndCheckValue(t, "struct", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.String(StringOpt{
+				Name:    "f format",
+				Value:   "json",
+				EnvVar:  "FORMAT",
+				Choices: []string{"json", "yaml", "table"},
+			})
+		})
+		restore()
+	}
+}
+
+func TestStringsOptWithChoices(t *testing.T) {
+	cases := []struct {
+		args             []string
+		env              map[string]string
+		expectedOptValue []string
+	}{
+		{[]string{"app"}, nil, nil},
+		{[]string{"app", "-o", "stderr", "-o", "stdout"}, nil, []string{"stderr", "stdout"}},
+		{[]string{"app"}, map[string]string{"OUTPUTS": "stdout"}, []string{"stdout"}},
+		{[]string{"app"}, map[string]string{"OUTPUTS": "stdout,file"}, nil},
+	}
+
+	for _, cas := range cases {
+		restore := setAndRestoreEnv(cas.env)
+		runAppAndCheckValue(t, "struct", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.Strings(StringsOpt{
+				Name:    "o output",
+				EnvVar:  "OUTPUTS",
+				Choices: []string{"stdout", "stderr"},
+			})
+		})
+		restore()
+	}
+}
+
+func TestChoicesRejected(t *testing.T) {
+	defer suppressOutput()()
+
+	app := App("app", "")
+	app.ErrorHandling = flag.ContinueOnError
+	app.String(StringOpt{Name: "f format", Choices: []string{"json", "yaml", "table"}})
+	app.Strings(StringsOpt{Name: "o output", Choices: []string{"stdout", "stderr"}})
+	app.Action = func() {
+		t.Fatal("action should not have been called")
+	}
+
+	var valueErr *InvalidValueError
+
+	err := app.Run([]string{"app", "-f", "xml"})
+	require.True(t, errors.As(err, &valueErr))
+	require.EqualError(t, err, `invalid value "xml" for --format: must be one of json, yaml, table`)
+
+	err = app.Run([]string{"app", "-o", "stdout", "--output=file"})
+	require.True(t, errors.As(err, &valueErr))
+	require.EqualError(t, err, `invalid value "file" for --output: must be one of stdout, stderr`)
+}
+
+func TestChoicesHelp(t *testing.T) {
+	var out, stdErr string
+	defer captureAndRestoreOutput(&out, &stdErr)()
+	defer exitShouldBeCalledWith(t, 0, new(bool))()
+
+	app := App("app", "")
+	app.String(StringOpt{Name: "f format", Value: "json", Desc: "Output format", Choices: []string{"json", "yaml"}})
+	app.String(StringArg{Name: "MODE", Choices: []string{"fast", "safe"}})
+	app.Run([]string{"app", "-h"})
+
+	require.Contains(t, stdErr, `Output format (one of: json, yaml) (default "json")`)
+	require.Contains(t, stdErr, "MODE           (one of: fast, safe)")
+}
+
+func TestChoicesCompletion(t *testing.T) {
+	complete := func(args ...string) []string {
+		var out string
+		defer captureAndRestoreOutput(&out, nil)()
+		defer exitShouldBeCalledWith(t, 0, new(bool))()
+
+		app := App("app", "")
+		app.String(StringOpt{Name: "f format", Choices: []string{"json", "yaml", "table"}})
+		app.Strings(StringsOpt{Name: "o output", Choices: []string{"stdout", "stderr"}})
+		app.Run(append([]string{"app", "__complete"}, args...))
+		return strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	}
+
+	require.Equal(t, []string{"json", "yaml", "table"}, complete("-f", ""))
+	require.Equal(t, []string{"table"}, complete("--format", "t"))
+	require.Equal(t, []string{"--output=stdout", "--output=stderr"}, complete("--output=std"))
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -15,7 +16,16 @@ Cli represents the structure of a CLI app. It should be constructed using the Ap
 */
 type Cli struct {
 	*Cmd
-	version *cliVersion
+	// Handle SIGINT and SIGTERM: the first signal cancels the context returned by Cmd.Context() and lets the After interceptors run,
+	// a second one exits the app immediately with SignalExitCode
+	HandleSignals bool
+	// The exit code used when a second signal is received while HandleSignals is set (130 if not set)
+	SignalExitCode int
+	// Accept any unique prefix of a long option name or of a sub command name, e.g. --verb for --verbose or rem for remote
+	AllowAbbreviations bool
+
+	version   *cliVersion
+	dumpNames []string
 }
 
 type cliVersion struct {
@@ -41,6 +51,7 @@ func App(name, desc string) *Cli {
 			optionsIdx:    map[string]*container.Container{},
 			argsIdx:       map[string]*container.Container{},
 			ErrorHandling: flag.ExitOnError,
+			config:        &config{},
 		},
 	}
 }
@@ -65,15 +76,27 @@ func (cli *Cli) Version(name, version string) {
 	cli.version = &cliVersion{version, option}
 }
 
-func (cli *Cli) parse(args []string, entry, inFlow, outFlow *flow.Step) error {
+func (cli *Cli) parse(args []string, offset int, entry, inFlow, outFlow *flow.Step) error {
 	// We overload Cmd.parse() and handle cases that only apply to the CLI command, like versioning
 	// After that, we just call Cmd.parse() for the default behavior
+	cli.abbreviations = cli.AllowAbbreviations
 	if cli.versionSetAndRequested(args) {
 		cli.PrintVersion()
 		cli.onError(errVersionRequested)
 		return nil
 	}
-	return cli.Cmd.parse(args, entry, inFlow, outFlow)
+	if cli.completionRequested(args) {
+		cli.printCompletion(args[1:])
+		cli.onError(errCompletionRequested)
+		return nil
+	}
+	format, pos, dump := cli.dumpRequested(args)
+	cli.dumpFormat = format
+	cli.dumpPos = offset + pos
+	if dump {
+		args = append(append([]string{}, args[:pos]...), args[pos+1:]...)
+	}
+	return cli.Cmd.parse(args, offset, entry, inFlow, outFlow)
 }
 
 func (cli *Cli) versionSetAndRequested(args []string) bool {
@@ -94,15 +117,30 @@ Run uses the app configuration (specs, commands, ...) to parse the args slice
 and to execute the matching command.
 
 In case of an incorrect usage, and depending on the configured ErrorHandling policy,
-it may return an error, panic or exit
+it may return an error, panic or exit.
+
+The error returned by an ActionE, BeforeE or AfterE function is returned as is.
 */
 func (cli *Cli) Run(args []string) error {
+	return cli.RunContext(context.Background(), args)
+}
+
+/*
+RunContext is like Run, but the provided context is passed to the ActionE, BeforeE and AfterE functions
+*/
+func (cli *Cli) RunContext(ctx context.Context, args []string) error {
 	if err := cli.doInit(); err != nil {
 		panic(err)
 	}
+	if cli.HandleSignals {
+		var stop func()
+		ctx, stop = cli.handleSignals(ctx)
+		defer stop()
+	}
+	cli.ctx = ctx
 	inFlow := &flow.Step{Desc: "RootIn", Exiter: exiter}
 	outFlow := &flow.Step{Desc: "RootOut", Exiter: exiter}
-	return cli.parse(args[1:], inFlow, inFlow, outFlow)
+	return cli.parse(args[1:], 1, inFlow, inFlow, outFlow)
 }
 
 /*
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"flag"
 
 	"github.com/stretchr/testify/require"
@@ -2292,6 +2294,177 @@ func TestBeforeAndAfterFlowOrderWhenMultipleAftersPanic(t *testing.T) {
 	require.Equal(t, 7, counter)
 }
 
+func callCheckerE(t *testing.T, wanted int, counter *int, err error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		require.Equal(t, wanted, *counter)
+		*counter++
+		return err
+	}
+}
+
+func TestBeforeAndAfterFlowOrderWithErrorFuncs(t *testing.T) {
+	counter := 0
+
+	app := App("app", "")
+
+	app.BeforeE = callCheckerE(t, 0, &counter, nil)
+	app.Command("c", "", func(c *Cmd) {
+		c.Before = callChecker(t, 1, &counter)
+		c.BeforeE = callCheckerE(t, 2, &counter, nil)
+		c.ActionE = callCheckerE(t, 3, &counter, nil)
+		c.AfterE = callCheckerE(t, 4, &counter, nil)
+	})
+	app.After = callChecker(t, 5, &counter)
+
+	require.NoError(t,
+		app.Run([]string{"app", "c"}))
+	require.Equal(t, 6, counter)
+}
+
+func TestActionEError(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	counter := 0
+	actionErr := errors.New("action failed")
+
+	app := App("app", "")
+
+	app.Before = callChecker(t, 0, &counter)
+	app.Command("c", "", func(c *Cmd) {
+		c.Before = callChecker(t, 1, &counter)
+		c.ActionE = callCheckerE(t, 2, &counter, actionErr)
+		c.After = callChecker(t, 3, &counter)
+	})
+	app.After = callChecker(t, 4, &counter)
+
+	err := app.Run([]string{"app", "c"})
+	require.Equal(t, actionErr, err)
+	require.Equal(t, 5, counter)
+}
+
+func TestBeforeEError(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	counter := 0
+	beforeErr := errors.New("before failed")
+
+	app := App("app", "")
+
+	app.Before = callChecker(t, 0, &counter)
+	app.Command("c", "", func(c *Cmd) {
+		c.BeforeE = callCheckerE(t, 1, &counter, beforeErr)
+		c.Command("cc", "", func(cc *Cmd) {
+			cc.Before = func() {
+				t.Fatalf("should not have been called")
+			}
+			cc.Action = func() {
+				t.Fatalf("should not have been called")
+			}
+		})
+		c.After = func() {
+			t.Fatalf("should not have been called")
+		}
+	})
+	app.After = callChecker(t, 2, &counter)
+
+	err := app.Run([]string{"app", "c", "cc"})
+	require.Equal(t, beforeErr, err)
+	require.Equal(t, 3, counter)
+}
+
+func TestAfterEError(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	counter := 0
+	afterErr := errors.New("after failed")
+
+	app := App("app", "")
+
+	app.Command("c", "", func(c *Cmd) {
+		c.Action = callChecker(t, 0, &counter)
+		c.AfterE = callCheckerE(t, 1, &counter, afterErr)
+	})
+	app.After = callChecker(t, 2, &counter)
+
+	err := app.Run([]string{"app", "c"})
+	require.Equal(t, afterErr, err)
+	require.Equal(t, 3, counter)
+}
+
+func TestActionEAndAfterEErrors(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	counter := 0
+	actionErr := errors.New("action failed")
+	afterErr := errors.New("after failed")
+
+	app := App("app", "")
+
+	app.Command("c", "", func(c *Cmd) {
+		c.ActionE = callCheckerE(t, 0, &counter, actionErr)
+		c.AfterE = callCheckerE(t, 1, &counter, afterErr)
+	})
+	app.AfterE = callCheckerE(t, 2, &counter, afterErr)
+
+	err := app.Run([]string{"app", "c"})
+	require.Equal(t, actionErr, err, "the first error should be returned")
+	require.Equal(t, 3, counter)
+}
+
+func TestActionAndActionE(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	counter := 0
+
+	app := App("app", "")
+	app.Action = callChecker(t, 0, &counter)
+	app.ActionE = callCheckerE(t, 1, &counter, nil)
+
+	require.NoError(t, app.Run([]string{"app"}))
+	require.Equal(t, 2, counter)
+}
+
+func TestRunContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	var values []interface{}
+	record := func(ctx context.Context) error {
+		values = append(values, ctx.Value(key{}))
+		return nil
+	}
+
+	app := App("app", "")
+	app.BeforeE = record
+	app.Command("c", "", func(c *Cmd) {
+		c.Command("cc", "", func(cc *Cmd) {
+			cc.BeforeE = record
+			cc.ActionE = record
+			cc.AfterE = record
+		})
+	})
+	app.AfterE = record
+
+	require.NoError(t,
+		app.RunContext(ctx, []string{"app", "c", "cc"}))
+	require.Equal(t, []interface{}{"value", "value", "value", "value", "value"}, values)
+}
+
+func TestActionEIsEnoughToRunACommand(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	called := false
+	app := App("app", "")
+	app.ActionE = func(ctx context.Context) error {
+		called = true
+		return nil
+	}
+
+	require.NoError(t,
+		app.Run([]string{"app"}))
+	require.True(t, called)
+}
+
 func exitShouldBeCalledWith(t *testing.T, wantedExitCode int, called *bool) func() {
 	oldExiter := exiter
 	exiter = func(code int) {
@@ -1,11 +1,13 @@
 package cli
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/jawher/mow.cli/internal/container"
 	"github.com/jawher/mow.cli/internal/flow"
@@ -26,6 +28,19 @@ type Cmd struct {
 	Before func()
 	// The code to execute after this command or any of its children is matched
 	After func()
+	// The code to execute when this command is matched. A returned error skips the remaining actions, runs the After interceptors and is returned by Run.
+	// If Action is set too, both are called: Action first, then ActionE
+	ActionE func(ctx context.Context) error
+	// The code to execute before this command or any of its children is matched. A returned error skips the remaining actions, runs the After interceptors and is returned by Run.
+	// If Before is set too, both are called: Before first, then BeforeE
+	BeforeE func(ctx context.Context) error
+	// The code to execute after this command or any of its children is matched. A returned error is returned by Run,
+	// unless an error was already returned by a previous step, in which case the first error is kept.
+	// If After is set too, both are called: After first, then AfterE
+	AfterE func(ctx context.Context) error
+	// The code to execute once the call arguments were parsed, e.g. to check the options and arguments values against each other.
+	// It is called before the Before and Action functions, and a returned error is handled like a usage error
+	Validate func() error
 	// The command options and arguments
 	Spec string
 	// The command long description to be shown when help is requested
@@ -34,6 +49,10 @@ type Cmd struct {
 	Hidden bool
 	// The command error handling strategy
 	ErrorHandling flag.ErrorHandling
+	// Disable the "Did you mean ...?" suggestions printed when an unknown command or option is used
+	DisableSuggestions bool
+	// The maximum edit distance between an unknown command or option and the suggested ones (2 if not set)
+	SuggestionsDistance int
 
 	init    CmdInitializer
 	name    string
@@ -46,9 +65,22 @@ type Cmd struct {
 	args       []*container.Container
 	argsIdx    map[string]*container.Container
 
+	constraints []constraint
+	// the required options which are not mandatory in the spec, checked after parsing
+	requiredOpts  []*container.Container
+	specGenerated bool
+
 	parents []string
+	config  *config
+	offset  int
+
+	dumpFormat    string
+	dumpPos       int
+	abbreviations bool
 
 	fsm *fsm.State
+
+	ctx context.Context
 }
 
 /*
@@ -100,6 +132,55 @@ type Floats64Param interface {
 	value(into *[]float64) (flag.Value, *[]float64)
 }
 
+/*
+DurationParam represents a duration option or argument
+*/
+type DurationParam interface {
+	value(into *time.Duration) (flag.Value, *time.Duration)
+}
+
+/*
+DurationsParam represents a duration slice option or argument
+*/
+type DurationsParam interface {
+	value(into *[]time.Duration) (flag.Value, *[]time.Duration)
+}
+
+/*
+TimeParam represents a time option or argument
+*/
+type TimeParam interface {
+	value(into *time.Time) (flag.Value, *time.Time)
+}
+
+/*
+TimesParam represents a time slice option or argument
+*/
+type TimesParam interface {
+	value(into *[]time.Time) (flag.Value, *[]time.Time)
+}
+
+/*
+StringMapParam represents a string map option or argument
+*/
+type StringMapParam interface {
+	value(into *map[string]string) (flag.Value, *map[string]string)
+}
+
+/*
+IntMapParam represents an int map option or argument
+*/
+type IntMapParam interface {
+	value(into *map[string]int) (flag.Value, *map[string]int)
+}
+
+/*
+CountParam represents an option counting its occurrences
+*/
+type CountParam interface {
+	value(into *int) (flag.Value, *int)
+}
+
 /*
 VarParam represents an custom option or argument where the type and format are controlled by the developer
 */
@@ -128,16 +209,19 @@ the last argument, init, is a function that will be called by mow.cli to further
 func (c *Cmd) Command(name, desc string, init CmdInitializer) {
 	aliases := strings.Fields(name)
 	c.commands = append(c.commands, &Cmd{
-		ErrorHandling: c.ErrorHandling,
-		name:          aliases[0],
-		aliases:       aliases,
-		desc:          desc,
-		init:          init,
-		commands:      []*Cmd{},
-		options:       []*container.Container{},
-		optionsIdx:    map[string]*container.Container{},
-		args:          []*container.Container{},
-		argsIdx:       map[string]*container.Container{},
+		ErrorHandling:       c.ErrorHandling,
+		DisableSuggestions:  c.DisableSuggestions,
+		SuggestionsDistance: c.SuggestionsDistance,
+		config:              c.config,
+		name:                aliases[0],
+		aliases:             aliases,
+		desc:                desc,
+		init:                init,
+		commands:            []*Cmd{},
+		options:             []*container.Container{},
+		optionsIdx:          map[string]*container.Container{},
+		args:                []*container.Container{},
+		argsIdx:             map[string]*container.Container{},
 	})
 }
 
@@ -152,9 +236,9 @@ func (c *Cmd) Bool(p BoolParam) *bool {
 
 	switch x := p.(type) {
 	case BoolOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Negatable: x.Negatable, Validate: validateValue(x.Validate, into)})
 	case BoolArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -169,13 +253,13 @@ It accepts either a pointer to a bool var and a BoolOpt or a BoolArg struct.
 The into parameter points to a variable (a pointer to a bool) which will be populated when the app is run and the call arguments get parsed
 */
 func (c *Cmd) BoolPtr(into *bool, p BoolParam) {
-	value, _ := p.value(into)
+	value, into := p.value(into)
 
 	switch x := p.(type) {
 	case BoolOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Negatable: x.Negatable, Validate: validateValue(x.Validate, into)})
 	case BoolArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -192,9 +276,9 @@ func (c *Cmd) String(p StringParam) *string {
 
 	switch x := p.(type) {
 	case StringOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Choices: x.Choices, Secret: x.Secret, NoOptDefVal: x.NoOptDefVal, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case StringArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Choices: x.Choices, Secret: x.Secret, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -209,13 +293,13 @@ It accepts either a pointer to a string var and a StringOpt or a StringArg struc
 The into parameter points to a variable (a pointer to a string) which will be populated when the app is run and the call arguments get parsed
 */
 func (c *Cmd) StringPtr(into *string, p StringParam) {
-	value, _ := p.value(into)
+	value, into := p.value(into)
 
 	switch x := p.(type) {
 	case StringOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Choices: x.Choices, Secret: x.Secret, NoOptDefVal: x.NoOptDefVal, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case StringArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Choices: x.Choices, Secret: x.Secret, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -232,9 +316,9 @@ func (c *Cmd) Int(p IntParam) *int {
 
 	switch x := p.(type) {
 	case IntOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, NoOptDefVal: x.NoOptDefVal, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case IntArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -249,13 +333,13 @@ It accepts either a pointer to a int var and a IntOpt or a IntArg struct.
 The into parameter points to a variable (a pointer to a int) which will be populated when the app is run and the call arguments get parsed
 */
 func (c *Cmd) IntPtr(into *int, p IntParam) {
-	value, _ := p.value(into)
+	value, into := p.value(into)
 
 	switch x := p.(type) {
 	case IntOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, NoOptDefVal: x.NoOptDefVal, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case IntArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -272,9 +356,9 @@ func (c *Cmd) Float64(p Float64Param) *float64 {
 
 	switch x := p.(type) {
 	case Float64Opt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, NoOptDefVal: x.NoOptDefVal, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case Float64Arg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -289,13 +373,13 @@ It accepts either a pointer to a float64 var and a Float64Opt or a Float64Arg st
 The into parameter points to a variable (a pointer to a float64) which will be populated when the app is run and the call arguments get parsed
 */
 func (c *Cmd) Float64Ptr(into *float64, p Float64Param) {
-	value, _ := p.value(into)
+	value, into := p.value(into)
 
 	switch x := p.(type) {
 	case Float64Opt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, NoOptDefVal: x.NoOptDefVal, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case Float64Arg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -312,9 +396,9 @@ func (c *Cmd) Strings(p StringsParam) *[]string {
 
 	switch x := p.(type) {
 	case StringsOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Choices: x.Choices, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case StringsArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Choices: x.Choices, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -329,13 +413,13 @@ It accepts either a pointer to a string slice var and a StringsOpt or a StringsA
 The into parameter points to a variable (a pointer to a string slice) which will be populated when the app is run and the call arguments get parsed
 */
 func (c *Cmd) StringsPtr(into *[]string, p StringsParam) {
-	value, _ := p.value(into)
+	value, into := p.value(into)
 
 	switch x := p.(type) {
 	case StringsOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Choices: x.Choices, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case StringsArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Choices: x.Choices, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -352,9 +436,9 @@ func (c *Cmd) Ints(p IntsParam) *[]int {
 
 	switch x := p.(type) {
 	case IntsOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case IntsArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -369,13 +453,13 @@ It accepts either a pointer to a int slice var and a IntsOpt or a IntsArg struct
 The into parameter points to a variable (a pointer to a int slice) which will be populated when the app is run and the call arguments get parsed
 */
 func (c *Cmd) IntsPtr(into *[]int, p IntsParam) {
-	value, _ := p.value(into)
+	value, into := p.value(into)
 
 	switch x := p.(type) {
 	case IntsOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case IntsArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -392,9 +476,9 @@ func (c *Cmd) Floats64(p Floats64Param) *[]float64 {
 
 	switch x := p.(type) {
 	case Floats64Opt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case Floats64Arg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -409,13 +493,289 @@ It accepts either a pointer to a float64 slice var and a Floats64Opt or a Floats
 The into parameter points to a variable (a pointer to a float64 slice) which will be populated when the app is run and the call arguments get parsed
 */
 func (c *Cmd) Floats64Ptr(into *[]float64, p Floats64Param) {
-	value, _ := p.value(into)
+	value, into := p.value(into)
 
 	switch x := p.(type) {
 	case Floats64Opt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
 	case Floats64Arg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+}
+
+/*
+Duration can be used to add a duration option or argument to a command.
+It accepts either a DurationOpt or a DurationArg struct.
+
+The result should be stored in a variable (a pointer to a time.Duration) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) Duration(p DurationParam) *time.Duration {
+	value, into := p.value(nil)
+
+	switch x := p.(type) {
+	case DurationOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, NoOptDefVal: x.NoOptDefVal, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case DurationArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+
+	return into
+}
+
+/*
+DurationPtr can be used to add a duration option or argument to a command.
+It accepts either a pointer to a time.Duration var and a DurationOpt or a DurationArg struct.
+
+The into parameter points to a variable (a pointer to a time.Duration) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) DurationPtr(into *time.Duration, p DurationParam) {
+	value, into := p.value(into)
+
+	switch x := p.(type) {
+	case DurationOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, NoOptDefVal: x.NoOptDefVal, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case DurationArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+}
+
+/*
+Durations can be used to add a duration slice option or argument to a command.
+It accepts either a DurationsOpt or a DurationsArg struct.
+
+The result should be stored in a variable (a pointer to a time.Duration slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) Durations(p DurationsParam) *[]time.Duration {
+	value, into := p.value(nil)
+
+	switch x := p.(type) {
+	case DurationsOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case DurationsArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+
+	return into
+}
+
+/*
+DurationsPtr can be used to add a duration slice option or argument to a command.
+It accepts either a pointer to a time.Duration slice var and a DurationsOpt or a DurationsArg struct.
+
+The into parameter points to a variable (a pointer to a time.Duration slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) DurationsPtr(into *[]time.Duration, p DurationsParam) {
+	value, into := p.value(into)
+
+	switch x := p.(type) {
+	case DurationsOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case DurationsArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+}
+
+/*
+Time can be used to add a time option or argument to a command.
+It accepts either a TimeOpt or a TimeArg struct.
+
+The result should be stored in a variable (a pointer to a time.Time) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) Time(p TimeParam) *time.Time {
+	value, into := p.value(nil)
+
+	switch x := p.(type) {
+	case TimeOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case TimeArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+
+	return into
+}
+
+/*
+TimePtr can be used to add a time option or argument to a command.
+It accepts either a pointer to a time.Time var and a TimeOpt or a TimeArg struct.
+
+The into parameter points to a variable (a pointer to a time.Time) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) TimePtr(into *time.Time, p TimeParam) {
+	value, into := p.value(into)
+
+	switch x := p.(type) {
+	case TimeOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case TimeArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+}
+
+/*
+Times can be used to add a time slice option or argument to a command.
+It accepts either a TimesOpt or a TimesArg struct.
+
+The result should be stored in a variable (a pointer to a time.Time slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) Times(p TimesParam) *[]time.Time {
+	value, into := p.value(nil)
+
+	switch x := p.(type) {
+	case TimesOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case TimesArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+
+	return into
+}
+
+/*
+TimesPtr can be used to add a time slice option or argument to a command.
+It accepts either a pointer to a time.Time slice var and a TimesOpt or a TimesArg struct.
+
+The into parameter points to a variable (a pointer to a time.Time slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) TimesPtr(into *[]time.Time, p TimesParam) {
+	value, into := p.value(into)
+
+	switch x := p.(type) {
+	case TimesOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case TimesArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+}
+
+/*
+StringMap can be used to add a string map option or argument to a command.
+It accepts either a StringMapOpt or a StringMapArg struct.
+
+The result should be stored in a variable (a pointer to a string map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) StringMap(p StringMapParam) *map[string]string {
+	value, into := p.value(nil)
+
+	switch x := p.(type) {
+	case StringMapOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case StringMapArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+
+	return into
+}
+
+/*
+StringMapPtr can be used to add a string map option or argument to a command.
+It accepts either a pointer to a map[string]string var and a StringMapOpt or a StringMapArg struct.
+
+The into parameter points to a variable (a pointer to a string map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) StringMapPtr(into *map[string]string, p StringMapParam) {
+	value, into := p.value(into)
+
+	switch x := p.(type) {
+	case StringMapOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case StringMapArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+}
+
+/*
+IntMap can be used to add an int map option or argument to a command.
+It accepts either a IntMapOpt or a IntMapArg struct.
+
+The result should be stored in a variable (a pointer to an int map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) IntMap(p IntMapParam) *map[string]int {
+	value, into := p.value(nil)
+
+	switch x := p.(type) {
+	case IntMapOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case IntMapArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+
+	return into
+}
+
+/*
+IntMapPtr can be used to add an int map option or argument to a command.
+It accepts either a pointer to a map[string]int var and a IntMapOpt or a IntMapArg struct.
+
+The into parameter points to a variable (a pointer to an int map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) IntMapPtr(into *map[string]int, p IntMapParam) {
+	value, into := p.value(into)
+
+	switch x := p.(type) {
+	case IntMapOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Required: x.Required, Validate: validateValue(x.Validate, into)})
+	case IntMapArg:
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+}
+
+/*
+Count can be used to add an option counting its occurrences to a command.
+It accepts a CountOpt struct.
+
+The result should be stored in a variable (a pointer to an int) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) Count(p CountParam) *int {
+	value, into := p.value(nil)
+
+	switch x := p.(type) {
+	case CountOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Validate: validateValue(x.Validate, into)})
+	default:
+		panic(fmt.Sprintf("Unhandled param %v", p))
+	}
+
+	return into
+}
+
+/*
+CountPtr can be used to add an option counting its occurrences to a command.
+It accepts a pointer to an int var and a CountOpt struct.
+
+The into parameter points to a variable (a pointer to an int) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) CountPtr(into *int, p CountParam) {
+	value, into := p.value(into)
+
+	switch x := p.(type) {
+	case CountOpt:
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: value, ValueSetByUser: x.SetByUser, Validate: validateValue(x.Validate, into)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
@@ -431,15 +791,19 @@ Instead, the VarOpt or VarOptArg structs hold the said value.
 func (c *Cmd) Var(p VarParam) {
 	switch x := p.(type) {
 	case VarOpt:
-		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: p.value(), ValueSetByUser: x.SetByUser})
+		c.mkOpt(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: p.value(), ValueSetByUser: x.SetByUser, Complete: x.Complete, NoOptDefVal: x.NoOptDefVal, Required: x.Required, Validate: validateValue(x.Validate, x.Value)})
 	case VarArg:
-		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: p.value(), ValueSetByUser: x.SetByUser})
+		c.mkArg(container.Container{Name: x.Name, Desc: x.Desc, EnvVar: x.EnvVar, HideValue: x.HideValue, Value: p.value(), ValueSetByUser: x.SetByUser, Complete: x.Complete, Validate: validateValue(x.Validate, x.Value)})
 	default:
 		panic(fmt.Sprintf("Unhandled param %v", p))
 	}
 }
 
 func (c *Cmd) doInit() error {
+	if c.fsm != nil {
+		return nil
+	}
+
 	if c.init != nil {
 		c.init(c)
 	}
@@ -451,36 +815,81 @@ func (c *Cmd) doInit() error {
 	}
 
 	if len(c.Spec) == 0 {
-		if len(c.options) > 0 {
-			c.Spec = "[OPTIONS] "
-		}
-		for _, arg := range c.args {
-			c.Spec += arg.Name + " "
-		}
+		c.specGenerated = true
+		c.Spec = c.generatedSpec(true)
 	}
 
 	tokens, err := lexer.Tokenize(c.Spec)
 	if err != nil {
 		return err
 	}
+	c.requiredOpts = c.requiredOptionsInOptions(tokens)
+
+	s, err := c.compileSpec(c.Spec, tokens)
+	if err != nil {
+		return err
+	}
+	c.fsm = s
+	return nil
+}
+
+// generatedSpec returns the spec used when none was specified: the required options if withRequired is set, then OPTIONS and the arguments
+func (c *Cmd) generatedSpec(withRequired bool) string {
+	spec := ""
+	for _, opt := range c.options {
+		if opt.Required && withRequired {
+			name := containerName(opt)
+			spec += fmt.Sprintf("%s=<%s> ", name, strings.TrimLeft(name, "-"))
+		}
+	}
+	if len(c.options) > 0 {
+		spec += "[OPTIONS] "
+	}
+	for _, arg := range c.args {
+		spec += arg.Name + " "
+	}
+	return spec
+}
 
+func (c *Cmd) compileSpec(spec string, tokens []*lexer.Token) (*fsm.State, error) {
 	params := parser.Params{
-		Spec:       c.Spec,
+		Spec:       spec,
 		Options:    c.options,
 		OptionsIdx: c.optionsIdx,
 		Args:       c.args,
 		ArgsIdx:    c.argsIdx,
 	}
-	s, err := parser.Parse(tokens, params)
-	if err != nil {
+	return parser.Parse(tokens, params)
+}
+
+// walk calls fn on c and then on all of its visible sub commands, depth first and in declaration order.
+// Every visited command is initialized first.
+func (c *Cmd) walk(fn func(*Cmd) error) error {
+	if err := c.doInit(); err != nil {
 		return err
 	}
-	c.fsm = s
+	if c.Hidden {
+		return nil
+	}
+	if err := fn(c); err != nil {
+		return err
+	}
+	for _, sub := range c.commands {
+		if err := sub.walk(fn); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// path returns the full command path, e.g. "app remote add"
+func (c *Cmd) path() string {
+	full := append([]string{}, c.parents...)
+	return strings.Join(append(full, c.name), " ")
+}
+
 func (c *Cmd) onError(err error) {
-	if err == errHelpRequested || err == errVersionRequested {
+	if err == errHelpRequested || err == errVersionRequested || err == errCompletionRequested || err == errConfigPrinted {
 		if c.ErrorHandling == flag.ExitOnError {
 			exiter(0)
 		}
@@ -515,19 +924,8 @@ func (c *Cmd) PrintLongHelp() {
 }
 
 func (c *Cmd) printHelp(longDesc bool) {
-	full := append(c.parents, c.name)
-	path := strings.Join(full, " ")
-	fmt.Fprintf(stdErr, "\nUsage: %s", path)
-
-	spec := strings.TrimSpace(c.Spec)
-	if len(spec) > 0 {
-		fmt.Fprintf(stdErr, " %s", spec)
-	}
-
-	if len(c.commands) > 0 {
-		fmt.Fprint(stdErr, " COMMAND [arg...]")
-	}
-	fmt.Fprint(stdErr, "\n\n")
+	path := c.path()
+	fmt.Fprintf(stdErr, "\nUsage: %s\n\n", c.usage())
 
 	desc := c.desc
 	if longDesc && len(c.LongDesc) > 0 {
@@ -543,11 +941,7 @@ func (c *Cmd) printHelp(longDesc bool) {
 		fmt.Fprint(w, "\t\nArguments:\t\n")
 
 		for _, arg := range c.args {
-			var (
-				env   = formatEnvVarsForHelp(arg.EnvVar)
-				value = formatValueForHelp(arg.HideValue, arg.DefaultValue)
-			)
-			printTabbedRow(w, arg.Name, joinStrings(arg.Desc, env, value))
+			printTabbedRow(w, arg.Name, formatDescForHelp(arg))
 		}
 	}
 
@@ -555,12 +949,15 @@ func (c *Cmd) printHelp(longDesc bool) {
 		fmt.Fprint(w, "\t\nOptions:\t\n")
 
 		for _, opt := range c.options {
-			var (
-				optNames = formatOptNamesForHelp(opt)
-				env      = formatEnvVarsForHelp(opt.EnvVar)
-				value    = formatValueForHelp(opt.HideValue, opt.DefaultValue)
-			)
-			printTabbedRow(w, optNames, joinStrings(opt.Desc, env, value))
+			printTabbedRow(w, formatOptNamesForHelp(opt), formatDescForHelp(opt))
+		}
+	}
+
+	if len(c.constraints) > 0 {
+		fmt.Fprint(w, "\t\nConstraints:\t\n")
+
+		for _, cons := range c.constraints {
+			fmt.Fprintf(w, "  %s\n", cons)
 		}
 	}
 
@@ -592,6 +989,33 @@ func (c *Cmd) printHelp(longDesc bool) {
 	w.Flush()
 }
 
+// usage returns the command path followed by its spec, e.g. "app remote add [OPTIONS] NAME URL"
+func (c *Cmd) usage() string {
+	res := c.path()
+
+	spec := strings.TrimSpace(c.Spec)
+	if len(spec) > 0 {
+		res += " " + spec
+	}
+
+	if len(c.commands) > 0 {
+		res += " COMMAND [arg...]"
+	}
+	return res
+}
+
+// formatDescForHelp returns the description of an option or an argument followed by whether it is required, its choices, env vars and default value
+func formatDescForHelp(con *container.Container) string {
+	var (
+		required    = formatRequiredForHelp(con.Required)
+		choices     = formatChoicesForHelp(con.Choices)
+		noOptDefVal = formatNoOptDefValForHelp(con.NoOptDefVal)
+		env         = formatEnvVarsForHelp(envVarsForHelp(con))
+		value       = formatValueForHelp(con.HideValue, con.DefaultValue)
+	)
+	return joinStrings(con.Desc, required, choices, noOptDefVal, env, value)
+}
+
 func formatOptNamesForHelp(o *container.Container) string {
 	short, long := "", ""
 
@@ -602,6 +1026,19 @@ func formatOptNamesForHelp(o *container.Container) string {
 
 		if len(n) > 2 && long == "" {
 			long = n
+			if o.Negatable {
+				long = "--[no-]" + n[2:]
+			}
+		}
+	}
+
+	if o.ValueOptional() {
+		// e.g. --color[=COLOR], or -c[=VALUE] for an option without a long name
+		switch {
+		case long != "":
+			long += "[=" + strings.ToUpper(long[2:]) + "]"
+		case short != "":
+			short += "[=VALUE]"
 		}
 	}
 
@@ -618,6 +1055,20 @@ func formatOptNamesForHelp(o *container.Container) string {
 	}
 }
 
+func formatRequiredForHelp(required bool) string {
+	if !required {
+		return ""
+	}
+	return "(required)"
+}
+
+func formatNoOptDefValForHelp(v string) string {
+	if v == "" {
+		return ""
+	}
+	return fmt.Sprintf("(%s if no value is given)", v)
+}
+
 func formatValueForHelp(hide bool, v string) string {
 	if hide {
 		return ""
@@ -630,6 +1081,38 @@ func formatValueForHelp(hide bool, v string) string {
 	return fmt.Sprintf("(default %s)", v)
 }
 
+func formatChoicesForHelp(choices []string) string {
+	if len(choices) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(one of: %s)", strings.Join(choices, ", "))
+}
+
+// completeChoices returns a completion function proposing the choices starting with the given prefix
+func completeChoices(choices []string) func(prefix string) []string {
+	return func(prefix string) []string {
+		var res []string
+		for _, c := range choices {
+			if strings.HasPrefix(c, prefix) {
+				res = append(res, c)
+			}
+		}
+		return res
+	}
+}
+
+// envVarsForHelp returns the env vars of con, followed by their VAR_FILE variant for the secrets
+func envVarsForHelp(con *container.Container) string {
+	if !con.Secret {
+		return con.EnvVar
+	}
+	var res []string
+	for _, v := range strings.Fields(con.EnvVar) {
+		res = append(res, v, v+"_FILE")
+	}
+	return strings.Join(res, " ")
+}
+
 func formatEnvVarsForHelp(envVars string) string {
 	if strings.TrimSpace(envVars) == "" {
 		return ""
@@ -647,7 +1130,8 @@ func formatEnvVarsForHelp(envVars string) string {
 	return res
 }
 
-func (c *Cmd) parse(args []string, entry, inFlow, outFlow *flow.Step) error {
+func (c *Cmd) parse(args []string, offset int, entry, inFlow, outFlow *flow.Step) error {
+	c.offset = offset
 	helpIndex := c.helpIndex(args)
 	nargsLen := c.getOptsAndArgs(args)
 
@@ -659,23 +1143,55 @@ func (c *Cmd) parse(args []string, entry, inFlow, outFlow *flow.Step) error {
 
 	// help was requested, but not for this command, skip the validation
 	if helpIndex >= 0 {
-		arg := args[nargsLen]
-		for _, sub := range c.commands {
-			if !sub.isAlias(arg) {
-				continue
-			}
-
-			if err := sub.doInit(); err != nil {
-				panic(err)
-			}
+		sub := c.abbreviatedSubCommand(args[nargsLen])
+		if sub == nil {
+			// impossible case
+			panic("wut")
+		}
 
-			return sub.parse(args[nargsLen+1:], entry, nil, nil)
+		if err := sub.doInit(); err != nil {
+			panic(err)
 		}
-		// impossible case
-		panic("wut")
+
+		sub.ctx = c.ctx
+		sub.dumpFormat = c.dumpFormat
+		sub.dumpPos = c.dumpPos
+		sub.abbreviations = c.abbreviations
+		return sub.parse(args[nargsLen+1:], offset+nargsLen+1, entry, nil, nil)
+	}
+
+	s := c.fsm
+	if c.dumpFormat != "" {
+		s = c.dumpFSM()
+	}
+	parse := s.Parse
+	if c.abbreviations {
+		parse = s.ParseAbbreviated
+	}
+	err := parse(args[:nargsLen])
+	if pos := c.abbreviatedCommandPos(err, args[:nargsLen]); pos >= 0 {
+		// the fsm stopped at an abbreviated sub command: it starts the sub command args
+		nargsLen = pos
+		err = parse(args[:nargsLen])
+	}
+	if err != nil {
+		commandPos := c.commandPos(err, args[:nargsLen])
+		err = c.usageError(err, args[:nargsLen], offset)
+		fmt.Fprintf(stdErr, "Error: %s\n", err.Error())
+		c.printSuggestions(args[:nargsLen], commandPos)
+		c.PrintHelp()
+		c.onError(err)
+		return err
+	}
+
+	if c.dumpFormat != "" && nargsLen == len(args) {
+		// print the config before validating it, to help understand why it is rejected
+		c.printConfig(stdOut)
+		c.onError(errConfigPrinted)
+		return nil
 	}
 
-	if err := c.fsm.Parse(args[:nargsLen]); err != nil {
+	if err := c.validate(); err != nil {
 		fmt.Fprintf(stdErr, "Error: %s\n", err.Error())
 		c.PrintHelp()
 		c.onError(err)
@@ -683,7 +1199,7 @@ func (c *Cmd) parse(args []string, entry, inFlow, outFlow *flow.Step) error {
 	}
 
 	newInFlow := &flow.Step{
-		Do:     c.Before,
+		Do:     c.hook(c.Before, c.BeforeE),
 		Error:  outFlow,
 		Desc:   fmt.Sprintf("%s.Before", c.name),
 		Exiter: exiter,
@@ -691,7 +1207,7 @@ func (c *Cmd) parse(args []string, entry, inFlow, outFlow *flow.Step) error {
 	inFlow.Success = newInFlow
 
 	newOutFlow := &flow.Step{
-		Do:      c.After,
+		Do:      c.hook(c.After, c.AfterE),
 		Success: outFlow,
 		Error:   outFlow,
 		Desc:    fmt.Sprintf("%s.After", c.name),
@@ -700,48 +1216,71 @@ func (c *Cmd) parse(args []string, entry, inFlow, outFlow *flow.Step) error {
 
 	args = args[nargsLen:]
 	if len(args) == 0 {
-		if c.Action != nil {
+		if c.Action != nil || c.ActionE != nil {
 			newInFlow.Success = &flow.Step{
-				Do:      c.Action,
+				Do:      c.hook(c.Action, c.ActionE),
 				Success: newOutFlow,
 				Error:   newOutFlow,
 				Desc:    fmt.Sprintf("%s.Action", c.name),
 				Exiter:  exiter,
 			}
 
-			entry.Run(nil)
-			return nil
+			return entry.RunE()
 		}
 		c.PrintHelp()
 		c.onError(nil)
 		return nil
 	}
 
-	arg := args[0]
-	for _, sub := range c.commands {
-		if sub.isAlias(arg) {
-			if err := sub.doInit(); err != nil {
-				panic(err)
-			}
-			return sub.parse(args[1:], entry, newInFlow, newOutFlow)
+	offset += nargsLen
+	if sub := c.abbreviatedSubCommand(args[0]); sub != nil {
+		if err := sub.doInit(); err != nil {
+			panic(err)
 		}
+		sub.ctx = c.ctx
+		sub.dumpFormat = c.dumpFormat
+		sub.dumpPos = c.dumpPos
+		sub.abbreviations = c.abbreviations
+		return sub.parse(args[1:], offset+1, entry, newInFlow, newOutFlow)
 	}
 
-	var err error
-	switch {
-	case strings.HasPrefix(arg, "-"):
-		err = fmt.Errorf("Error: illegal option %s", arg)
-		fmt.Fprintln(stdErr, err.Error())
-	default:
-		err = fmt.Errorf("Error: illegal input %s", arg)
-		fmt.Fprintln(stdErr, err.Error())
-	}
+	err = c.unmatchedArgError(args, 0, offset)
+	fmt.Fprintf(stdErr, "Error: %s\n", err.Error())
 	c.PrintHelp()
 	c.onError(err)
 	return err
 
 }
 
+/*
+Context returns the context of the current run, i.e. the one passed to RunContext, or context.Background() when Run was used.
+When the app's HandleSignals is set, the context is cancelled on the first SIGINT or SIGTERM
+*/
+func (c *Cmd) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// hook combines a plain interceptor and its error returning variant into a flow step code block
+func (c *Cmd) hook(f func(), fe func(ctx context.Context) error) func() {
+	if f == nil && fe == nil {
+		return nil
+	}
+	return func() {
+		if f != nil {
+			f()
+		}
+		if fe == nil {
+			return
+		}
+		if err := fe(c.Context()); err != nil {
+			panic(flow.ReturnedError{Err: err})
+		}
+	}
+}
+
 func (c *Cmd) helpIndex(args []string) int {
 	searchSet := []string{"-h", "--help"}
 	for i, arg := range args {
@@ -774,11 +1313,13 @@ func (c *Cmd) isFirstItemAmong(args []string, searchSet []string) bool {
 func (c *Cmd) getOptsAndArgs(args []string) int {
 	consumed := 0
 
-	for _, arg := range args {
-		for _, sub := range c.commands {
-			if sub.isAlias(arg) {
-				return consumed
-			}
+	for i, arg := range args {
+		if c.subCommand(arg) != nil {
+			return consumed
+		}
+		// an abbreviation is only a command if it is not the value of the preceding option
+		if c.abbreviations && c.pendingOption(args[:i]) == nil && c.abbreviatedSubCommand(arg) != nil {
+			return consumed
 		}
 		consumed++
 	}
@@ -32,7 +32,7 @@ func okCmd(t *testing.T, spec string, init CmdInitializer, args []string) {
 	require.Nil(t, err, "should parse")
 	t.Logf("testing spec %q with args: %#v", spec, args)
 	inFlow := &flow.Step{}
-	err = cmd.parse(args, inFlow, inFlow, &flow.Step{})
+	err = cmd.parse(args, 0, inFlow, inFlow, &flow.Step{})
 	require.Nil(t, err, "cmd parse should't fail")
 }
 
@@ -52,7 +52,7 @@ func failCmd(t *testing.T, spec string, init CmdInitializer, args []string) {
 	require.NoError(t, err, "should parse")
 	t.Logf("testing spec %q with args: %#v", spec, args)
 	inFlow := &flow.Step{}
-	err = cmd.parse(args, inFlow, inFlow, &flow.Step{})
+	err = cmd.parse(args, 0, inFlow, inFlow, &flow.Step{})
 	require.Error(t, err, "cmd parse should have failed")
 }
 
@@ -0,0 +1,442 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/jawher/mow.cli/internal/container"
+	"github.com/jawher/mow.cli/internal/matcher"
+	"github.com/jawher/mow.cli/internal/values"
+)
+
+const completeCommand = "__complete"
+
+type completionGenerator func(w io.Writer, name string, cmds []*Cmd)
+
+var completionGenerators = map[string]completionGenerator{
+	"bash": genBashCompletion,
+	"zsh":  genZshCompletion,
+	"fish": genFishCompletion,
+}
+
+/*
+GenerateCompletion writes to w a completion script for the app's commands, command aliases and options.
+The supported shells are bash, zsh and fish.
+
+Hidden commands are left out of the generated script.
+The candidates for the arguments and option values are fetched at runtime by calling the app with the hidden
+__complete command, which relies on the Complete functions of the options and arguments.
+*/
+func (cli *Cli) GenerateCompletion(shell string, w io.Writer) error {
+	gen, found := completionGenerators[shell]
+	if !found {
+		return fmt.Errorf("unsupported shell %q: must be one of bash, zsh or fish", shell)
+	}
+
+	var cmds []*Cmd
+	if err := cli.walk(func(c *Cmd) error {
+		cmds = append(cmds, c)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	gen(buf, cli.name, cmds)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+/*
+CompletionCommand adds a hidden command to the app which prints the completion script of the shell passed as argument:
+
+	Usage: app $name SHELL
+
+The generated script can then be loaded by the shell, e.g. in bash:
+
+	source <(app $name bash)
+*/
+func (cli *Cli) CompletionCommand(name string) {
+	cli.Command(name, "Generate a shell completion script", func(cmd *Cmd) {
+		cmd.Hidden = true
+		shell := cmd.StringArg("SHELL", "", "The target shell: bash, zsh or fish")
+
+		cmd.Action = func() {
+			if err := cli.GenerateCompletion(*shell, stdOut); err != nil {
+				fmt.Fprintf(stdErr, "Error: %s\n", err)
+				Exit(2)
+			}
+		}
+	})
+}
+
+func (cli *Cli) completionRequested(args []string) bool {
+	return cli.isFirstItemAmong(args, []string{completeCommand})
+}
+
+// printCompletion prints the completion candidates of the last of args, the ones before it being the already typed words
+func (cli *Cli) printCompletion(args []string) {
+	prefix := ""
+	if len(args) > 0 {
+		prefix = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	c := cli.Cmd
+	for {
+		nargsLen := c.getOptsAndArgs(args)
+		if nargsLen == len(args) {
+			break
+		}
+		sub := c.abbreviatedSubCommand(args[nargsLen])
+		if err := sub.doInit(); err != nil {
+			panic(err)
+		}
+		sub.abbreviations = c.abbreviations
+		c = sub
+		args = args[nargsLen+1:]
+	}
+
+	seen := map[string]bool{}
+	for _, candidate := range c.complete(args, prefix) {
+		if !strings.HasPrefix(candidate, prefix) || seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		fmt.Fprintln(stdOut, candidate)
+	}
+}
+
+// complete returns the candidates for an arg starting with prefix following args
+func (c *Cmd) complete(args []string, prefix string) []string {
+	if strings.HasPrefix(prefix, "-") && strings.Contains(prefix, "=") {
+		kv := strings.SplitN(prefix, "=", 2)
+		opt, found := c.optionsIdx[kv[0]]
+		if !found || opt.Complete == nil {
+			return nil
+		}
+		var res []string
+		for _, v := range opt.Complete(kv[1]) {
+			res = append(res, kv[0]+"="+v)
+		}
+		return res
+	}
+
+	if opt := c.pendingOption(args); opt != nil {
+		if opt.Complete == nil {
+			return nil
+		}
+		return opt.Complete(prefix)
+	}
+
+	completion := c.fsm.Complete(args, prefix)
+	candidates := completion.Candidates
+
+	if strings.HasPrefix(prefix, "-") {
+		if !completion.Consumed || optionsEnded(args) {
+			return candidates
+		}
+		// options can be matched out of order, so instead of relying on the matchers which could consume the next arg,
+		// try each option and keep the ones which can be consumed after args
+		for _, opt := range c.options {
+			for _, name := range append(append([]string{}, opt.Names...), opt.NegatedNames()...) {
+				if !strings.HasPrefix(name, prefix) {
+					continue
+				}
+				trial := append(append([]string{}, args...), name)
+				if !values.IsBool(opt.Value) && !opt.ValueOptional() {
+					trial = append(trial, "value")
+				}
+				if c.fsm.Complete(trial, "").Consumed {
+					candidates = append(candidates, name)
+				}
+			}
+		}
+		return candidates
+	}
+
+	if completion.Terminal {
+		for _, sub := range c.visibleCommands() {
+			candidates = append(candidates, sub.aliases...)
+		}
+	}
+	return candidates
+}
+
+// pendingOption returns the option expecting a value if it is the last of args, e.g. --name or -n
+func (c *Cmd) pendingOption(args []string) *container.Container {
+	if len(args) == 0 || optionsEnded(args) {
+		return nil
+	}
+
+	arg := args[len(args)-1]
+	switch {
+	case strings.HasPrefix(arg, "--"):
+		_, opt := matcher.LookupLongOpt(c.optionsIdx, arg, c.abbreviations)
+		if opt == nil || values.IsBool(opt.Value) || opt.ValueOptional() {
+			return nil
+		}
+		return opt
+	case strings.HasPrefix(arg, "-"):
+		for i := 1; i < len(arg); i++ {
+			opt, found := c.optionsIdx["-"+arg[i:i+1]]
+			if !found {
+				return nil
+			}
+			if !values.IsBool(opt.Value) {
+				if i == len(arg)-1 && !opt.ValueOptional() {
+					return opt
+				}
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func optionsEnded(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDynamicCompletion returns true if the candidates of some of the command's args cannot be known statically
+func (c *Cmd) hasDynamicCompletion() bool {
+	if len(c.args) > 0 {
+		return true
+	}
+	for _, opt := range c.options {
+		if !values.IsBool(opt.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleCommands returns the non hidden sub commands of c
+func (c *Cmd) visibleCommands() []*Cmd {
+	var res []*Cmd
+	for _, sub := range c.commands {
+		if err := sub.doInit(); err != nil {
+			panic(err)
+		}
+		if sub.Hidden {
+			continue
+		}
+		res = append(res, sub)
+	}
+	return res
+}
+
+// hasVisibleCommands checks if c has sub commands not known to be hidden, without initializing them
+func (c *Cmd) hasVisibleCommands() bool {
+	for _, sub := range c.commands {
+		if !sub.Hidden {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Cmd) optionNames() []string {
+	var res []string
+	for _, opt := range c.options {
+		res = append(res, opt.Names...)
+		res = append(res, opt.NegatedNames()...)
+	}
+	return res
+}
+
+var nonIdentChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func completionFuncName(name string) string {
+	return "_" + nonIdentChars.ReplaceAllString(name, "_") + "_completion"
+}
+
+func firstLine(s string) string {
+	return strings.TrimSpace(strings.SplitN(s, "\n", 2)[0])
+}
+
+// shellQuote single quotes s for bash and zsh
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// fishQuote single quotes s for fish
+func fishQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
+
+func writeCommandPathCases(w io.Writer, cmds []*Cmd, indent string) {
+	for _, c := range cmds {
+		for _, sub := range c.visibleCommands() {
+			var patterns []string
+			for _, alias := range sub.aliases {
+				patterns = append(patterns, shellQuote(c.path()+" "+alias))
+			}
+			fmt.Fprintf(w, "%s%s) cmdpath=%s ;;\n", indent, strings.Join(patterns, "|"), shellQuote(sub.path()))
+		}
+	}
+}
+
+func genBashCompletion(w io.Writer, name string, cmds []*Cmd) {
+	fn := completionFuncName(name)
+
+	fmt.Fprintf(w, "# bash completion for %s\n\n", name)
+	fmt.Fprintf(w, "%s_dynamic() {\n", fn)
+	fmt.Fprint(w, "    local IFS=$'\\n'\n")
+	fmt.Fprintf(w, "    COMPREPLY=($(\"${COMP_WORDS[0]}\" %s \"${COMP_WORDS[@]:1:COMP_CWORD}\" 2>/dev/null))\n", completeCommand)
+	fmt.Fprint(w, "    if [[ ${#COMPREPLY[@]} -eq 0 ]]; then\n")
+	fmt.Fprint(w, "        COMPREPLY=($(compgen -f -- \"${COMP_WORDS[COMP_CWORD]}\"))\n")
+	fmt.Fprint(w, "    fi\n")
+	fmt.Fprint(w, "}\n\n")
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprint(w, "    local cur cmdpath i\n")
+	fmt.Fprint(w, "    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	fmt.Fprintf(w, "    cmdpath=%s\n", shellQuote(name))
+	fmt.Fprint(w, "    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	fmt.Fprint(w, "        case \"${cmdpath} ${COMP_WORDS[i]}\" in\n")
+	writeCommandPathCases(w, cmds, "            ")
+	fmt.Fprint(w, "        esac\n")
+	fmt.Fprint(w, "    done\n\n")
+	fmt.Fprint(w, "    COMPREPLY=()\n")
+	fmt.Fprint(w, "    case \"${cmdpath}\" in\n")
+	for _, c := range cmds {
+		var words []string
+		for _, sub := range c.visibleCommands() {
+			words = append(words, sub.aliases...)
+		}
+		words = append(words, c.optionNames()...)
+
+		fmt.Fprintf(w, "        %s)\n", shellQuote(c.path()))
+		if c.hasDynamicCompletion() {
+			fmt.Fprintf(w, "            %s_dynamic\n", fn)
+		} else {
+			fmt.Fprintf(w, "            COMPREPLY=($(compgen -W %s -- \"${cur}\"))\n", shellQuote(strings.Join(words, " ")))
+		}
+		fmt.Fprint(w, "            ;;\n")
+	}
+	fmt.Fprint(w, "    esac\n")
+	fmt.Fprint(w, "}\n\n")
+	fmt.Fprintf(w, "complete -F %s %s\n", fn, name)
+}
+
+func genZshCompletion(w io.Writer, name string, cmds []*Cmd) {
+	fn := completionFuncName(name)
+	candidate := func(word, desc string) string {
+		return shellQuote(strings.Replace(word, ":", `\:`, -1) + ":" + firstLine(desc))
+	}
+
+	fmt.Fprintf(w, "#compdef %s\n\n", name)
+	fmt.Fprintf(w, "%s_dynamic() {\n", fn)
+	fmt.Fprint(w, "    local -a candidates\n")
+	fmt.Fprintf(w, "    candidates=(${(f)\"$(${words[1]} %s \"${(@)words[2,CURRENT]}\" 2>/dev/null)\"})\n", completeCommand)
+	fmt.Fprint(w, "    if (( ${#candidates} )); then\n")
+	fmt.Fprint(w, "        compadd -a candidates\n")
+	fmt.Fprint(w, "    else\n")
+	fmt.Fprint(w, "        _files\n")
+	fmt.Fprint(w, "    fi\n")
+	fmt.Fprint(w, "}\n\n")
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprint(w, "    local cmdpath i\n")
+	fmt.Fprint(w, "    local -a candidates\n")
+	fmt.Fprintf(w, "    cmdpath=%s\n", shellQuote(name))
+	fmt.Fprint(w, "    for ((i = 2; i < CURRENT; i++)); do\n")
+	fmt.Fprint(w, "        case \"${cmdpath} ${words[i]}\" in\n")
+	writeCommandPathCases(w, cmds, "            ")
+	fmt.Fprint(w, "        esac\n")
+	fmt.Fprint(w, "    done\n\n")
+	fmt.Fprint(w, "    case \"${cmdpath}\" in\n")
+	for _, c := range cmds {
+		fmt.Fprintf(w, "        %s)\n", shellQuote(c.path()))
+		if c.hasDynamicCompletion() {
+			fmt.Fprintf(w, "            %s_dynamic\n", fn)
+			fmt.Fprint(w, "            ;;\n")
+			continue
+		}
+		fmt.Fprint(w, "            candidates=(\n")
+		for _, sub := range c.visibleCommands() {
+			for _, alias := range sub.aliases {
+				fmt.Fprintf(w, "                %s\n", candidate(alias, sub.desc))
+			}
+		}
+		for _, opt := range c.options {
+			for _, n := range append(append([]string{}, opt.Names...), opt.NegatedNames()...) {
+				fmt.Fprintf(w, "                %s\n", candidate(n, opt.Desc))
+			}
+		}
+		fmt.Fprint(w, "            )\n")
+		fmt.Fprintf(w, "            _describe %s candidates\n", shellQuote(c.path()))
+		fmt.Fprint(w, "            ;;\n")
+	}
+	fmt.Fprint(w, "    esac\n")
+	fmt.Fprint(w, "}\n\n")
+	fmt.Fprintf(w, "compdef %s %s\n", fn, name)
+}
+
+func genFishCompletion(w io.Writer, name string, cmds []*Cmd) {
+	fn := strings.TrimPrefix(completionFuncName(name), "_")
+
+	fmt.Fprintf(w, "# fish completion for %s\n\n", name)
+	fmt.Fprintf(w, "function __%s_path\n", fn)
+	fmt.Fprint(w, "    set -l words (commandline -opc)\n")
+	fmt.Fprint(w, "    set -e words[1]\n")
+	fmt.Fprintf(w, "    set -l cmdpath %s\n", fishQuote(name))
+	fmt.Fprint(w, "    for word in $words\n")
+	fmt.Fprint(w, "        switch \"$cmdpath $word\"\n")
+	for _, c := range cmds {
+		for _, sub := range c.visibleCommands() {
+			var patterns []string
+			for _, alias := range sub.aliases {
+				patterns = append(patterns, fishQuote(c.path()+" "+alias))
+			}
+			fmt.Fprintf(w, "            case %s\n", strings.Join(patterns, " "))
+			fmt.Fprintf(w, "                set cmdpath %s\n", fishQuote(sub.path()))
+		}
+	}
+	fmt.Fprint(w, "        end\n")
+	fmt.Fprint(w, "    end\n")
+	fmt.Fprint(w, "    echo $cmdpath\n")
+	fmt.Fprint(w, "end\n\n")
+	fmt.Fprintf(w, "function __%s_dynamic\n", fn)
+	fmt.Fprint(w, "    set -l words (commandline -opc)\n")
+	fmt.Fprint(w, "    set -l current (commandline -ct)\n")
+	fmt.Fprint(w, "    set -l cmd $words[1]\n")
+	fmt.Fprint(w, "    set -e words[1]\n")
+	fmt.Fprintf(w, "    $cmd %s $words \"$current\" 2>/dev/null\n", completeCommand)
+	fmt.Fprint(w, "end\n")
+
+	for _, c := range cmds {
+		cond := fishQuote(fmt.Sprintf("test (__%s_path) = %s", fn, fishQuote(c.path())))
+		fmt.Fprint(w, "\n")
+		if c.hasDynamicCompletion() {
+			fmt.Fprintf(w, "complete -c %s -n %s -a %s\n", name, cond, fishQuote(fmt.Sprintf("(__%s_dynamic)", fn)))
+		} else {
+			fmt.Fprintf(w, "complete -c %s -n %s -f\n", name, cond)
+		}
+		for _, sub := range c.visibleCommands() {
+			fmt.Fprintf(w, "complete -c %s -n %s -f -a %s -d %s\n", name, cond, fishQuote(strings.Join(sub.aliases, " ")), fishQuote(firstLine(sub.desc)))
+		}
+		for _, opt := range c.options {
+			line := fmt.Sprintf("complete -c %s -n %s", name, cond)
+			for _, n := range append(append([]string{}, opt.Names...), opt.NegatedNames()...) {
+				if strings.HasPrefix(n, "--") {
+					line += " -l " + fishQuote(n[2:])
+				} else {
+					line += " -s " + fishQuote(n[1:])
+				}
+			}
+			if !values.IsBool(opt.Value) && !opt.ValueOptional() {
+				line += " -r"
+			}
+			fmt.Fprintf(w, "%s -d %s\n", line, fishQuote(firstLine(opt.Desc)))
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		shell := shell
+		t.Run(shell, func(t *testing.T) {
+			app := App("app", "App Desc")
+			app.Version("V version", "app 1.0")
+			app.BoolOpt("v verbose", false, "Verbose mode")
+
+			app.Command("remote r", "Manage remotes", func(cmd *Cmd) {
+				cmd.Command("add", "Add a remote", func(cmd *Cmd) {
+					cmd.StringOpt("t track", "", "Branch to track\nMultiple lines")
+					cmd.StringArg("NAME", "", "The remote name")
+					cmd.StringArg("URL", "", "The remote url")
+				})
+				cmd.Command("rm", "Remove a remote", func(cmd *Cmd) {
+					cmd.BoolOpt("f", false, "Don't ask")
+				})
+			})
+			app.Command("secret", "A hidden command", func(cmd *Cmd) {
+				cmd.Hidden = true
+				cmd.Command("child", "Should not be visible", func(cmd *Cmd) {})
+			})
+			app.Command("it's", "A command with a quote", func(cmd *Cmd) {})
+			app.CompletionCommand("completion")
+
+			var out bytes.Buffer
+			require.NoError(t, app.GenerateCompletion(shell, &out))
+
+			filename := fmt.Sprintf("testdata/completion-%s.txt", shell)
+
+			if *genGolden {
+				require.NoError(t,
+					ioutil.WriteFile(filename, out.Bytes(), 0644))
+			}
+
+			expected, e := ioutil.ReadFile(filename)
+			require.NoError(t, e, "Failed to read the expected completion script from %s", filename)
+
+			require.Equal(t, string(expected), out.String())
+		})
+	}
+}
+
+func TestGenerateCompletionUnsupportedShell(t *testing.T) {
+	app := App("app", "")
+
+	var out bytes.Buffer
+	err := app.GenerateCompletion("powershell", &out)
+	require.Error(t, err)
+	require.Empty(t, out.String())
+}
+
+func TestCompletionCommand(t *testing.T) {
+	var out, stdErr string
+	defer captureAndRestoreOutput(&out, &stdErr)()
+
+	defer exitShouldNotCalled(t)()
+
+	app := App("app", "")
+	app.BoolOpt("v verbose", false, "")
+	app.Command("status", "", func(cmd *Cmd) {})
+	app.CompletionCommand("completion")
+
+	require.NoError(t,
+		app.Run([]string{"app", "completion", "bash"}))
+
+	var expected bytes.Buffer
+	require.NoError(t, app.GenerateCompletion("bash", &expected))
+
+	require.Equal(t, expected.String(), out)
+	require.Contains(t, out, "status")
+}
+
+func TestCompletionCommandUnsupportedShell(t *testing.T) {
+	defer suppressOutput()()
+
+	exitCalled := false
+	defer exitShouldBeCalledWith(t, 2, &exitCalled)()
+
+	app := App("app", "")
+	app.CompletionCommand("completion")
+	require.NoError(t,
+		app.Run([]string{"app", "completion", "powershell"}))
+
+	require.True(t, exitCalled, "exit should have been called")
+}
+
+func TestComplete(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected []string
+	}{
+		{args: []string{"app", "__complete"}, expected: []string{"deploy", "d", "remote"}},
+		{args: []string{"app", "__complete", ""}, expected: []string{"deploy", "d", "remote"}},
+		{args: []string{"app", "__complete", "r"}, expected: []string{"remote"}},
+		{args: []string{"app", "__complete", "-"}, expected: []string{"-v", "--verbose"}},
+		{args: []string{"app", "__complete", "-v", "de"}, expected: []string{"deploy"}},
+		{args: []string{"app", "__complete", "deploy", ""}, expected: []string{"prod", "staging"}},
+		{args: []string{"app", "__complete", "d", "s"}, expected: []string{"staging"}},
+		{args: []string{"app", "__complete", "deploy", "-"}, expected: []string{"-r", "--region", "--json", "--yaml"}},
+		{args: []string{"app", "__complete", "deploy", "--json", "-"}, expected: []string{"-r", "--region"}},
+		{args: []string{"app", "__complete", "deploy", "--region", ""}, expected: []string{"eu", "us"}},
+		{args: []string{"app", "__complete", "deploy", "-r", "e"}, expected: []string{"eu"}},
+		{args: []string{"app", "__complete", "deploy", "--region=u"}, expected: []string{"--region=us"}},
+		{args: []string{"app", "__complete", "deploy", "prod", ""}, expected: []string{"api", "web"}},
+		{args: []string{"app", "__complete", "deploy", "prod", "api", ""}, expected: nil},
+		{args: []string{"app", "__complete", "deploy", "--", "-"}, expected: []string{"-x"}},
+		{args: []string{"app", "__complete", "remote", ""}, expected: []string{"add"}},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			var out string
+			defer captureAndRestoreOutput(&out, nil)()
+
+			exitCalled := false
+			defer exitShouldBeCalledWith(t, 0, &exitCalled)()
+
+			app := App("app", "")
+			app.BoolOpt("v verbose", false, "")
+			app.Command("deploy d", "", func(cmd *Cmd) {
+				cmd.Spec = "[-r=<region>] [--json | --yaml] ENV [SERVICE]"
+				cmd.String(StringOpt{Name: "r region", Complete: func(prefix string) []string {
+					return []string{"eu", "us"}
+				}})
+				cmd.BoolOpt("json", false, "")
+				cmd.BoolOpt("yaml", false, "")
+				cmd.String(StringArg{Name: "ENV", Complete: func(prefix string) []string {
+					return []string{"prod", "staging", "-x"}
+				}})
+				cmd.String(StringArg{Name: "SERVICE", Complete: func(prefix string) []string {
+					return []string{"api", "web"}
+				}})
+				cmd.Action = func() {
+					t.Errorf("action should not have been called")
+				}
+			})
+			app.Command("remote", "", func(cmd *Cmd) {
+				cmd.Command("add", "", func(cmd *Cmd) {})
+				cmd.Command("secret", "", func(cmd *Cmd) {
+					cmd.Hidden = true
+				})
+			})
+
+			require.NoError(t,
+				app.Run(cas.args))
+			require.True(t, exitCalled, "exit should have been called")
+
+			var lines []string
+			if out != "" {
+				lines = strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			}
+			require.Equal(t, cas.expected, lines)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/jawher/mow.cli/internal/container"
+	"github.com/jawher/mow.cli/internal/values"
+)
+
+/*
+ConfigDecoder decodes the content of a config file into a tree of values.
+
+Nested objects must be decoded as map[string]interface{} and lists as []interface{}.
+*/
+type ConfigDecoder interface {
+	Decode(data []byte) (map[string]interface{}, error)
+}
+
+/*
+ConfigDecoderFunc is an adapter to use an ordinary function as a ConfigDecoder
+*/
+type ConfigDecoderFunc func(data []byte) (map[string]interface{}, error)
+
+/*
+Decode calls f(data)
+*/
+func (f ConfigDecoderFunc) Decode(data []byte) (map[string]interface{}, error) {
+	return f(data)
+}
+
+/*
+JSONConfig is a ConfigDecoder for JSON config files
+*/
+var JSONConfig ConfigDecoder = ConfigDecoderFunc(func(data []byte) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+})
+
+// config holds the decoded config file, shared by a command and all of its sub commands
+type config struct {
+	path string
+	tree map[string]interface{}
+}
+
+/*
+LoadConfig reads the config file at path using decoder (JSONConfig if nil) and uses it to fill the options and arguments
+of the app and of its sub commands.
+
+A value is looked up using the path of its command (without the app name) followed by the option long name
+(or the lowercased argument name), e.g. `remote.add.verbose`, either as nested objects or as a dotted key.
+
+Env vars take precedence over the config file, and the call arguments over both.
+*/
+func (cli *Cli) LoadConfig(path string, decoder ConfigDecoder) error {
+	if decoder == nil {
+		decoder = JSONConfig
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	tree, err := decoder.Decode(data)
+	if err != nil {
+		return fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+
+	cli.config.path = path
+	cli.config.tree = tree
+
+	// the options and arguments declared before the config was loaded
+	for _, con := range cli.options {
+		cli.setFromConfig(con)
+	}
+	for _, con := range cli.args {
+		cli.setFromConfig(con)
+	}
+	return nil
+}
+
+// setFromConfig fills con from the config file, unless it was already set from an env var
+func (c *Cmd) setFromConfig(con *container.Container) {
+	if c.config == nil || c.config.tree == nil || con.ValueSetFromEnv {
+		return
+	}
+	key := c.configKey(con)
+	v, found := lookupConfig(c.config.tree, key)
+	if !found {
+		return
+	}
+	con.ValueSetFromConfig = values.SetFromConfig(con.Value, v, con.Check)
+	if con.ValueSetFromConfig {
+		con.ValueConfigKey = strings.Join(key, ".")
+	}
+}
+
+// configKey returns the path of con in the config file, e.g. [remote add verbose]
+func (c *Cmd) configKey(con *container.Container) []string {
+	var key []string
+	if len(c.parents) > 0 {
+		key = append(key, c.parents[1:]...)
+		key = append(key, c.name)
+	}
+
+	if len(con.Names) == 0 {
+		return append(key, strings.ToLower(con.Name))
+	}
+	name := con.Names[0]
+	for _, n := range con.Names {
+		if strings.HasPrefix(n, "--") {
+			name = n
+			break
+		}
+	}
+	return append(key, strings.TrimLeft(name, "-"))
+}
+
+// lookupConfig finds the value at key in tree, where every level can either be a nested object or a dotted key
+func lookupConfig(tree map[string]interface{}, key []string) (interface{}, bool) {
+	for i := len(key); i > 0; i-- {
+		v, found := tree[strings.Join(key[:i], ".")]
+		if !found {
+			continue
+		}
+		if i == len(key) {
+			return v, true
+		}
+		if sub, ok := v.(map[string]interface{}); ok {
+			if res, found := lookupConfig(sub, key[i:]); found {
+				return res, true
+			}
+		}
+	}
+	return nil, false
+}
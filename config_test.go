@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	app := App("app", "")
+	verbose := app.BoolOpt("v verbose", false, "")
+
+	require.NoError(t, app.LoadConfig("testdata/config.json", nil))
+
+	name := app.StringOpt("n name", "default", "")
+	tags := app.StringsOpt("t tags", nil, "")
+	labels := app.StringMapOpt("l labels", nil, "")
+	timeout := app.DurationOpt("timeout", time.Second, "")
+	format := app.String(StringOpt{Name: "format", Value: "json", Choices: []string{"json", "yaml"}})
+	missing := app.StringOpt("missing", "default", "")
+
+	var (
+		force bool
+		port  int
+		url   string
+	)
+	app.Command("remote", "", func(cmd *Cmd) {
+		cmd.Command("add", "", func(cmd *Cmd) {
+			cmd.Spec = "[-f] [--url] [PORT]"
+			cmd.BoolOptPtr(&force, "f force", false, "")
+			cmd.IntArgPtr(&port, "PORT", 80, "")
+			cmd.StringOptPtr(&url, "url", "", "")
+			cmd.Action = func() {}
+		})
+	})
+
+	called := false
+	app.Action = func() {
+		called = true
+	}
+
+	require.NoError(t, app.Run([]string{"app"}))
+	require.True(t, called)
+
+	require.True(t, *verbose, "options declared before loading the config should be filled too")
+	require.Equal(t, "from-config", *name)
+	require.Equal(t, []string{"a", "b"}, *tags)
+	require.Equal(t, map[string]string{"env": "prod", "team": "core"}, *labels)
+	require.Equal(t, 30*time.Second, *timeout)
+	require.Equal(t, "json", *format, "a config value not in the choices should be ignored")
+	require.Equal(t, "default", *missing)
+
+	require.NoError(t, app.Run([]string{"app", "remote", "add"}))
+	require.True(t, force)
+	require.Equal(t, 8080, port)
+	require.Equal(t, "http://config", url)
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+	defer setAndRestoreEnv(map[string]string{"NAME": "from-env"})()
+
+	app := App("app", "")
+	app.Spec = "[-n] [-t...]"
+	name := app.String(StringOpt{Name: "n name", Value: "default", EnvVar: "NAME"})
+	tags := app.Strings(StringsOpt{Name: "t tags", EnvVar: "UNSET_TAGS"})
+	require.NoError(t, app.LoadConfig("testdata/config.json", JSONConfig))
+
+	app.Action = func() {}
+
+	require.NoError(t, app.Run([]string{"app"}))
+	require.Equal(t, "from-env", *name)
+	require.Equal(t, []string{"a", "b"}, *tags)
+
+	require.NoError(t, app.Run([]string{"app", "-n", "from-flag", "-t", "c"}))
+	require.Equal(t, "from-flag", *name)
+	require.Equal(t, []string{"c"}, *tags)
+}
+
+func TestConfigSatisfiesRequiredOption(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	app := App("app", "")
+	app.Spec = "--name"
+	name := app.StringOpt("name", "", "")
+	require.NoError(t, app.LoadConfig("testdata/config.json", nil))
+
+	app.Action = func() {}
+
+	require.NoError(t, app.Run([]string{"app"}))
+	require.Equal(t, "from-config", *name)
+}
+
+func TestLoadConfigCustomDecoder(t *testing.T) {
+	decoder := ConfigDecoderFunc(func(data []byte) (map[string]interface{}, error) {
+		res := map[string]interface{}{}
+		for _, line := range strings.Split(string(data), "\n") {
+			kv := strings.SplitN(line, "=", 2)
+			if len(kv) == 2 {
+				res[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+			}
+		}
+		return res, nil
+	})
+
+	app := App("app", "")
+	name := app.StringOpt("name", "", "")
+	require.NoError(t, app.LoadConfig("testdata/config.ini", decoder))
+	require.Equal(t, "from-ini", *name)
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	app := App("app", "")
+
+	require.Error(t, app.LoadConfig("testdata/missing.json", nil))
+
+	err := app.LoadConfig("testdata/config.ini", JSONConfig)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid config file testdata/config.ini")
+
+	boom := errors.New("boom")
+	err = app.LoadConfig("testdata/config.json", ConfigDecoderFunc(func([]byte) (map[string]interface{}, error) {
+		return nil, boom
+	}))
+	require.EqualError(t, err, "invalid config file testdata/config.json: boom")
+}
+
+func TestLookupConfig(t *testing.T) {
+	tree := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b.c": 1,
+			"b": map[string]interface{}{
+				"d": 2,
+			},
+		},
+		"a.b.e": 3,
+		"f":     4,
+	}
+
+	cases := []struct {
+		key      string
+		expected interface{}
+		found    bool
+	}{
+		{"a.b.c", 1, true},
+		{"a.b.d", 2, true},
+		{"a.b.e", 3, true},
+		{"f", 4, true},
+		{"a.b.x", nil, false},
+		{"f.x", nil, false},
+	}
+
+	for _, cas := range cases {
+		v, found := lookupConfig(tree, strings.Split(cas.key, "."))
+		require.Equal(t, cas.found, found, cas.key)
+		require.Equal(t, cas.expected, v, cas.key)
+	}
+}
@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jawher/mow.cli/internal/container"
+	"github.com/jawher/mow.cli/internal/lexer"
+	"github.com/jawher/mow.cli/internal/matcher"
+	"github.com/jawher/mow.cli/internal/values"
+)
+
+// constraint is a rule on the options and arguments of a command which is checked after the call arguments were parsed
+type constraint interface {
+	// check returns an error if the rule is broken
+	check(c *Cmd) error
+	// String describes the rule for the help message
+	String() string
+}
+
+/*
+MutuallyExclusive declares that at most one of the named options or arguments can be set, e.g.
+
+	cmd.MutuallyExclusive("file", "url", "stdin")
+
+A name is either an option name, with or without the dashes (e.g. `f`, `file` or `--file`), or an argument name (e.g. `SRC`).
+An option or argument counts as set when its value comes from the call arguments, an env var or a config file.
+The options and arguments must be declared before calling MutuallyExclusive, which panics otherwise.
+When the rule is broken, a MutuallyExclusiveError is returned.
+*/
+func (c *Cmd) MutuallyExclusive(names ...string) {
+	c.constraints = append(c.constraints, &mutuallyExclusive{params: c.constrainedParams("MutuallyExclusive", names...)})
+}
+
+/*
+ExactlyOne declares that one and only one of the named options or arguments must be set, e.g.
+
+	cmd.ExactlyOne("file", "url", "stdin")
+
+The names are resolved as in MutuallyExclusive.
+When the rule is broken, an ExactlyOneError is returned.
+*/
+func (c *Cmd) ExactlyOne(names ...string) {
+	c.constraints = append(c.constraints, &exactlyOne{params: c.constrainedParams("ExactlyOne", names...)})
+}
+
+/*
+RequiredTogether declares that the named options or arguments must either be all set or none of them, e.g.
+
+	cmd.RequiredTogether("user", "password")
+
+The names are resolved as in MutuallyExclusive.
+When the rule is broken, a RequiredTogetherError is returned.
+*/
+func (c *Cmd) RequiredTogether(names ...string) {
+	c.constraints = append(c.constraints, &requiredTogether{params: c.constrainedParams("RequiredTogether", names...)})
+}
+
+/*
+RequiredIf declares that the option or argument named name must be set when the one named ifName is, e.g.
+
+	cmd.RequiredIf("tls-key", "tls-cert")
+
+The names are resolved as in MutuallyExclusive.
+When the rule is broken, a RequiredIfError is returned.
+*/
+func (c *Cmd) RequiredIf(name, ifName string) {
+	params := c.constrainedParams("RequiredIf", name, ifName)
+	c.constraints = append(c.constraints, &requiredIf{param: params[0], ifParam: params[1]})
+}
+
+func (c *Cmd) constrainedParams(rule string, names ...string) []*container.Container {
+	res := make([]*container.Container, len(names))
+	for i, name := range names {
+		con := c.lookupParam(name)
+		if con == nil {
+			panic(fmt.Sprintf("%s: no option or argument named %q", rule, name))
+		}
+		res[i] = con
+	}
+	return res
+}
+
+// requiredOptionsInOptions returns the required options only matched by the OPTIONS token of the spec,
+// which the fsm doesn't enforce
+func (c *Cmd) requiredOptionsInOptions(tokens []*lexer.Token) []*container.Container {
+	inOptions := false
+	inSpec := map[*container.Container]bool{}
+	for _, t := range tokens {
+		switch t.Typ {
+		case lexer.TTOptions:
+			inOptions = true
+		case lexer.TTShortOpt, lexer.TTLongOpt:
+			inSpec[c.optionsIdx[t.Val]] = true
+		case lexer.TTOptSeq:
+			for i := range t.Val {
+				inSpec[c.optionsIdx["-"+t.Val[i:i+1]]] = true
+			}
+		}
+	}
+	if !inOptions {
+		return nil
+	}
+
+	var res []*container.Container
+	for _, opt := range c.options {
+		if opt.Required && !inSpec[opt] {
+			res = append(res, opt)
+		}
+	}
+	return res
+}
+
+// missingRequiredOption returns the required option made mandatory by the generated spec which is absent from args, if any
+func (c *Cmd) missingRequiredOption(args []string) *container.Container {
+	if !c.specGenerated {
+		return nil
+	}
+	for _, opt := range c.options {
+		if opt.Required && !opt.ValueSetExternally() && !c.optionGiven(opt, args) {
+			return opt
+		}
+	}
+	return nil
+}
+
+// optionGiven returns true if opt appears in args before a --
+func (c *Cmd) optionGiven(opt *container.Container, args []string) bool {
+	for _, arg := range args {
+		switch {
+		case arg == "--":
+			return false
+		case strings.HasPrefix(arg, "--"):
+			if _, o := matcher.LookupLongOpt(c.optionsIdx, strings.SplitN(arg, "=", 2)[0], c.abbreviations); o == opt {
+				return true
+			}
+		case strings.HasPrefix(arg, "-"):
+			for i := 1; i < len(arg); i++ {
+				o := c.optionsIdx["-"+arg[i:i+1]]
+				if o == opt {
+					return true
+				}
+				if o == nil || !values.IsBool(o.Value) {
+					// the rest of the arg is a value
+					break
+				}
+			}
+		}
+	}
+	return false
+}
+
+// checkConstraints returns an error for the first missing required option or broken constraint, if any
+func (c *Cmd) checkConstraints() error {
+	for _, opt := range c.requiredOpts {
+		if !isSet(opt) {
+			return &MissingOptionError{Command: c.path(), Option: containerName(opt)}
+		}
+	}
+	for _, cons := range c.constraints {
+		if err := cons.check(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isSet(con *container.Container) bool {
+	return con.ValueSetFromArgs || con.ValueSetExternally()
+}
+
+func paramNames(cons []*container.Container) []string {
+	res := make([]string, len(cons))
+	for i, con := range cons {
+		res[i] = containerName(con)
+	}
+	return res
+}
+
+func formatNames(names []string) string {
+	res := names[len(names)-1]
+	if len(names) > 1 {
+		res = strings.Join(names[:len(names)-1], ", ") + " and " + res
+	}
+	return res
+}
+
+type mutuallyExclusive struct {
+	params []*container.Container
+}
+
+func (m *mutuallyExclusive) check(c *Cmd) error {
+	var set []*container.Container
+	for _, con := range m.params {
+		if isSet(con) {
+			set = append(set, con)
+		}
+	}
+	if len(set) < 2 {
+		return nil
+	}
+	return &MutuallyExclusiveError{Command: c.path(), Names: paramNames(set)}
+}
+
+func (m *mutuallyExclusive) String() string {
+	return fmt.Sprintf("%s are mutually exclusive", formatNames(paramNames(m.params)))
+}
+
+type exactlyOne struct {
+	params []*container.Container
+}
+
+func (e *exactlyOne) check(c *Cmd) error {
+	var set []*container.Container
+	for _, con := range e.params {
+		if isSet(con) {
+			set = append(set, con)
+		}
+	}
+	if len(set) == 1 {
+		return nil
+	}
+	return &ExactlyOneError{Command: c.path(), Names: paramNames(e.params), Set: paramNames(set)}
+}
+
+func (e *exactlyOne) String() string {
+	return fmt.Sprintf("exactly one of %s is required", formatNames(paramNames(e.params)))
+}
+
+type requiredTogether struct {
+	params []*container.Container
+}
+
+func (r *requiredTogether) check(c *Cmd) error {
+	var set, missing []*container.Container
+	for _, con := range r.params {
+		if isSet(con) {
+			set = append(set, con)
+		} else {
+			missing = append(missing, con)
+		}
+	}
+	if len(set) == 0 || len(missing) == 0 {
+		return nil
+	}
+	return &RequiredTogetherError{Command: c.path(), Set: paramNames(set), Missing: paramNames(missing)}
+}
+
+func (r *requiredTogether) String() string {
+	return fmt.Sprintf("%s must be used together", formatNames(paramNames(r.params)))
+}
+
+type requiredIf struct {
+	param   *container.Container
+	ifParam *container.Container
+}
+
+func (r *requiredIf) check(c *Cmd) error {
+	if !isSet(r.ifParam) || isSet(r.param) {
+		return nil
+	}
+	return &RequiredIfError{Command: c.path(), Name: containerName(r.param), IfName: containerName(r.ifParam)}
+}
+
+func (r *requiredIf) String() string {
+	return fmt.Sprintf("%s is required with %s", containerName(r.param), containerName(r.ifParam))
+}
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutuallyExclusive(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"app"}, ""},
+		{[]string{"app", "-f", "x"}, "x"},
+		{[]string{"app", "--url", "u"}, ""},
+		{[]string{"app", "src"}, ""},
+	}
+
+	for _, cas := range cases {
+		runAppAndCheckValue(t, "MutuallyExclusive", cas.args, cas.expected, func(app *Cli) interface{} {
+			app.Spec = "[OPTIONS] [SRC]"
+			file := app.StringOpt("f file", "", "")
+			app.StringOpt("url", "", "")
+			app.StringArg("SRC", "", "")
+			app.MutuallyExclusive("file", "url", "SRC")
+			return file
+		})
+	}
+}
+
+func TestRequiredTogether(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"app"}, ""},
+		{[]string{"app", "--user", "u", "--password", "p"}, "u"},
+	}
+
+	for _, cas := range cases {
+		runAppAndCheckValue(t, "RequiredTogether", cas.args, cas.expected, func(app *Cli) interface{} {
+			user := app.StringOpt("user", "", "")
+			app.StringOpt("password", "", "")
+			app.RequiredTogether("--user", "password")
+			return user
+		})
+	}
+}
+
+func TestExactlyOne(t *testing.T) {
+	cases := []struct {
+		args     []string
+		env      map[string]string
+		expected string
+	}{
+		{[]string{"app", "-f", "x"}, nil, "x"},
+		{[]string{"app", "--url", "u"}, nil, ""},
+		{[]string{"app", "src"}, nil, ""},
+		{[]string{"app"}, map[string]string{"URL": "u"}, ""},
+	}
+
+	for _, cas := range cases {
+		restore := setAndRestoreEnv(cas.env)
+		runAppAndCheckValue(t, "ExactlyOne", cas.args, cas.expected, func(app *Cli) interface{} {
+			app.Spec = "[OPTIONS] [SRC]"
+			file := app.StringOpt("f file", "", "")
+			app.String(StringOpt{Name: "url", EnvVar: "URL"})
+			app.StringArg("SRC", "", "")
+			app.ExactlyOne("file", "url", "SRC")
+			return file
+		})
+		restore()
+	}
+}
+
+func TestRequiredIf(t *testing.T) {
+	cases := []struct {
+		args     []string
+		env      map[string]string
+		expected string
+	}{
+		{[]string{"app"}, nil, ""},
+		{[]string{"app", "--tls-key", "k"}, nil, ""},
+		{[]string{"app", "--tls-cert", "c", "--tls-key", "k"}, nil, "c"},
+		{[]string{"app", "--tls-cert", "c"}, map[string]string{"TLS_KEY": "k"}, "c"},
+	}
+
+	for _, cas := range cases {
+		restore := setAndRestoreEnv(cas.env)
+		runAppAndCheckValue(t, "RequiredIf", cas.args, cas.expected, func(app *Cli) interface{} {
+			cert := app.StringOpt("tls-cert", "", "")
+			app.String(StringOpt{Name: "tls-key", EnvVar: "TLS_KEY"})
+			app.RequiredIf("tls-key", "tls-cert")
+			return cert
+		})
+		restore()
+	}
+}
+
+func TestConstraintsErrors(t *testing.T) {
+	cases := []struct {
+		config   func(*Cli)
+		args     []string
+		expected error
+		message  string
+	}{
+		{
+			config: func(app *Cli) {
+				app.StringOpt("f file", "", "")
+				app.StringOpt("url", "", "")
+				app.BoolOpt("stdin", false, "")
+				app.MutuallyExclusive("file", "url", "stdin")
+			},
+			args:     []string{"app", "--url", "u", "-f", "x"},
+			expected: &MutuallyExclusiveError{Command: "app", Names: []string{"--file", "--url"}},
+			message:  "--file and --url cannot be used together",
+		},
+		{
+			config: func(app *Cli) {
+				app.Spec = "[OPTIONS] [SRC]"
+				app.BoolOpt("stdin", false, "")
+				app.StringArg("SRC", "", "")
+				app.MutuallyExclusive("stdin", "SRC")
+			},
+			args:     []string{"app", "--stdin", "src"},
+			expected: &MutuallyExclusiveError{Command: "app", Names: []string{"--stdin", "SRC"}},
+			message:  "--stdin and SRC cannot be used together",
+		},
+		{
+			config: func(app *Cli) {
+				app.StringOpt("f file", "", "")
+				app.StringOpt("url", "", "")
+				app.BoolOpt("stdin", false, "")
+				app.ExactlyOne("file", "url", "stdin")
+			},
+			args:     []string{"app"},
+			expected: &ExactlyOneError{Command: "app", Names: []string{"--file", "--url", "--stdin"}, Set: []string{}},
+			message:  "exactly one of --file, --url and --stdin is required",
+		},
+		{
+			config: func(app *Cli) {
+				app.StringOpt("f file", "", "")
+				app.StringOpt("url", "", "")
+				app.BoolOpt("stdin", false, "")
+				app.ExactlyOne("file", "url", "stdin")
+			},
+			args:     []string{"app", "--stdin", "-f", "x"},
+			expected: &ExactlyOneError{Command: "app", Names: []string{"--file", "--url", "--stdin"}, Set: []string{"--file", "--stdin"}},
+			message:  "--file and --stdin cannot be used together",
+		},
+		{
+			config: func(app *Cli) {
+				app.StringOpt("user", "", "")
+				app.StringOpt("password", "", "")
+				app.RequiredTogether("--user", "password")
+			},
+			args:     []string{"app", "--password", "p"},
+			expected: &RequiredTogetherError{Command: "app", Set: []string{"--password"}, Missing: []string{"--user"}},
+			message:  "--password must be used with --user",
+		},
+		{
+			config: func(app *Cli) {
+				app.StringOpt("tls-cert", "", "")
+				app.StringOpt("tls-key", "", "")
+				app.RequiredIf("tls-key", "tls-cert")
+			},
+			args:     []string{"app", "--tls-cert", "c"},
+			expected: &RequiredIfError{Command: "app", Name: "--tls-key", IfName: "--tls-cert"},
+			message:  "--tls-key is required with --tls-cert",
+		},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			defer suppressOutput()()
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+			cas.config(app)
+			app.Action = func() {
+				t.Fatal("action should not have been called")
+			}
+
+			err := app.Run(cas.args)
+			require.Equal(t, cas.expected, err)
+			require.EqualError(t, err, cas.message)
+		})
+	}
+}
+
+func TestConstraintsSkipAction(t *testing.T) {
+	defer suppressOutput()()
+
+	called := false
+	defer exitShouldBeCalledWith(t, 2, &called)()
+
+	app := App("app", "")
+	app.BoolOpt("a", false, "")
+	app.BoolOpt("b", false, "")
+	app.MutuallyExclusive("a", "b")
+	app.Action = func() {
+		t.Fatal("action should not have been called")
+	}
+
+	app.Run([]string{"app", "-a", "-b"})
+	require.True(t, called, "exit should have been called")
+}
+
+func TestConstraintsUnknownName(t *testing.T) {
+	app := App("app", "")
+	app.BoolOpt("a", false, "")
+
+	require.PanicsWithValue(t, `MutuallyExclusive: no option or argument named "b"`, func() {
+		app.MutuallyExclusive("a", "b")
+	})
+}
+
+func TestConstraintsHelp(t *testing.T) {
+	var out, stdErr string
+	defer captureAndRestoreOutput(&out, &stdErr)()
+	defer exitShouldBeCalledWith(t, 0, new(bool))()
+
+	app := App("app", "")
+	app.Spec = "[OPTIONS] [SRC]"
+	app.StringOpt("f file", "", "")
+	app.StringOpt("url", "", "")
+	app.BoolOpt("stdin", false, "")
+	app.StringOpt("user", "", "")
+	app.StringOpt("password", "", "")
+	app.StringOpt("tls-cert", "", "")
+	app.StringOpt("tls-key", "", "")
+	app.StringArg("SRC", "", "")
+	app.MutuallyExclusive("file", "url", "stdin", "SRC")
+	app.RequiredTogether("--user", "password")
+	app.RequiredIf("tls-key", "tls-cert")
+	app.ExactlyOne("url", "SRC")
+
+	app.Run([]string{"app", "-h"})
+
+	require.Contains(t, stdErr, "Constraints:")
+	require.Contains(t, stdErr, "  --file, --url, --stdin and SRC are mutually exclusive\n")
+	require.Contains(t, stdErr, "  --user and --password must be used together\n")
+	require.Contains(t, stdErr, "  --tls-key is required with --tls-cert\n")
+	require.Contains(t, stdErr, "  exactly one of --url and SRC is required\n")
+}
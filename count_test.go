@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountOpt(t *testing.T) {
+	cases := []struct {
+		args     []string
+		env      map[string]string
+		expected int
+		force    bool
+		name     string
+	}{
+		{args: []string{"app"}},
+		{args: []string{"app", "-v"}, expected: 1},
+		{args: []string{"app", "-vvv"}, expected: 3},
+		{args: []string{"app", "-v", "--verbose", "-vv"}, expected: 4},
+		{args: []string{"app", "-vfv"}, expected: 2, force: true},
+		{args: []string{"app", "-fvv"}, expected: 2, force: true},
+		{args: []string{"app", "-vvnx"}, expected: 2, name: "x"},
+		{args: []string{"app", "-vn", "x", "-v"}, expected: 2, name: "x"},
+		{args: []string{"app", "--verbose=3", "-v"}, expected: 4},
+		{args: []string{"app"}, env: map[string]string{"VERBOSITY": "2"}, expected: 2},
+		{args: []string{"app", "-v"}, env: map[string]string{"VERBOSITY": "2"}, expected: 1},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q %v", cas.args, cas.env), func(t *testing.T) {
+			defer exitShouldNotCalled(t)()
+			defer setAndRestoreEnv(cas.env)()
+
+			app := App("app", "")
+			verbose := app.Count(CountOpt{Name: "v verbose", EnvVar: "VERBOSITY"})
+			force := app.BoolOpt("f force", false, "")
+			name := app.StringOpt("n name", "", "")
+
+			called := false
+			app.Action = func() {
+				called = true
+			}
+
+			require.NoError(t, app.Run(cas.args))
+			require.True(t, called)
+			require.Equal(t, cas.expected, *verbose)
+			require.Equal(t, cas.force, *force)
+			require.Equal(t, cas.name, *name)
+		})
+	}
+}
+
+func TestCountOptWithSpec(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	app := App("app", "")
+	app.Spec = "[-v] ARG"
+	var verbose int
+	app.CountOptPtr(&verbose, "v", 0, "")
+	app.StringArg("ARG", "", "")
+	app.Action = func() {}
+
+	require.NoError(t, app.Run([]string{"app", "-vvv", "x"}))
+	require.Equal(t, 3, verbose)
+}
+
+func TestCountOptShortForm(t *testing.T) {
+	runAppAndCheckValue(t, "short", []string{"app", "-qq"}, 2, func(app *Cli) interface{} {
+		return app.CountOpt("q quiet", 0, "")
+	})
+	runAppAndCheckValue(t, "short-ptr", []string{"app", "-q", "--quiet"}, 2, func(app *Cli) interface{} {
+		var val int
+		app.CountOptPtr(&val, "q quiet", 0, "")
+		return &val
+	})
+}
@@ -33,7 +33,8 @@ Finally, in the main func, call Run passing in the arguments for parsing:
 Options
 
 To add one or more command line options (also known as flags), use one of the
-short-form StringOpt, StringsOpt, IntOpt, IntsOpt, Float64Opt, Floats64Opt, or BoolOpt methods on App (or
+short-form StringOpt, StringsOpt, IntOpt, IntsOpt, Float64Opt, Floats64Opt, DurationOpt, DurationsOpt,
+TimeOpt, TimesOpt, StringMapOpt, IntMapOpt, or BoolOpt methods on App (or
 Cmd if adding flags to a command or a subcommand). For example, to add a boolean
 flag to the cp command that specifies recursive mode, use the following:
 
@@ -55,7 +56,8 @@ The last parameter is the description to be shown in help messages.
 
 There is also a second set of methods on App called String, Strings, Int, Ints,
 and Bool, which accept a long-form struct of the type: cli.StringOpt,
-cli.StringsOpt, cli.IntOpt, cli.IntsOpt, cli.Float64Opt, cli.Floats64Opt, cli.BoolOpt. The struct describes the
+cli.StringsOpt, cli.IntOpt, cli.IntsOpt, cli.Float64Opt, cli.Floats64Opt, cli.DurationOpt, cli.DurationsOpt,
+cli.TimeOpt, cli.TimesOpt, cli.StringMapOpt, cli.IntMapOpt, cli.BoolOpt. The struct describes the
 option and allows the use of additional features not available in the short-form
 methods described above:
 
@@ -110,7 +112,7 @@ String, int and float options:
     --extra=value  double dash for longer option names, equal sign followed by the value
     --extra value  double dash for longer option names, space followed by the value
 
-Slice options (StringsOpt, IntsOpt, Floats64Opt) where option is repeated to accumulate
+Slice options (StringsOpt, IntsOpt, Floats64Opt, DurationsOpt, TimesOpt) where option is repeated to accumulate
 values in a slice:
 
     -e PATH:/bin    -e PATH:/usr/bin     resulting slice contains ["/bin", "/usr/bin"]
@@ -119,12 +121,30 @@ values in a slice:
     --env PATH:/bin --env PATH:/usr/bin  resulting slice contains ["/bin", "/usr/bin"]
     --env=PATH:/bin --env=PATH:/usr/bin  resulting slice contains ["/bin", "/usr/bin"]
 
+Duration options and arguments accept the values understood by time.ParseDuration, e.g. 1h30m.
+Time options and arguments are parsed using the time.RFC3339 layout by default, which can be changed
+with the Layout field of the long-form structs:
+
+    since := app.Time(cli.TimeOpt{
+        Name:   "since",
+        Layout: "2006-01-02",
+        Desc:   "only show the entries after this date",
+    })
+
+Map options and arguments (StringMapOpt, IntMapOpt, StringMapArg, IntMapArg) accept key=value pairs,
+and fail if the same key is given twice:
+
+    -l env=prod --label team=core        resulting map contains {"env": "prod", "team": "core"}
+
+Their env vars contain a comma separated list of pairs, e.g. LABELS="env=prod,team=core".
+
 
 
 Arguments
 
 To add one or more command line arguments (not prefixed by dashes), use one of
-the short-form StringArg, StringsArg, IntArg, IntsArg, Float64Arg, Floats64Arg, or BoolArg methods on App
+the short-form StringArg, StringsArg, IntArg, IntsArg, Float64Arg, Floats64Arg, DurationArg, DurationsArg,
+TimeArg, TimesArg, StringMapArg, IntMapArg, or BoolArg methods on App
 (or Cmd if adding arguments to a command or subcommand). For example, to add two
 string arguments to our cp command, use the following calls:
 
@@ -697,5 +717,435 @@ value to decide whether or not to display the default value to users:
     }
 
 
+
+Shell Completion
+
+Completion scripts for bash, zsh and fish can be generated from the app's commands, command aliases and options
+(hidden commands are left out) using GenerateCompletion:
+
+    app.GenerateCompletion("bash", os.Stdout)
+
+Alternatively, CompletionCommand adds a hidden command to the app which prints the completion script of the requested shell:
+
+    app.CompletionCommand("completion")
+
+Users can then enable the completion with:
+
+    source <(app completion bash)
+
+The candidates for the values of options and arguments are fetched at runtime: the generated scripts call the app
+with the hidden __complete command followed by the words typed so far, and the app prints one candidate per line.
+Only the options and arguments still allowed by the spec at the cursor position are considered, and their candidates
+come from the Complete field of the option or argument struct:
+
+    env := app.String(cli.StringArg{
+        Name: "ENV",
+        Desc: "The target environment",
+        Complete: func(prefix string) []string {
+            return fetchEnvironments()
+        },
+    })
+
+The returned candidates are filtered by the app to keep only those starting with prefix.
+
+
+
+Man Pages
+
+GenerateManPages writes a man page for the app and for each one of its visible commands into a directory, e.g. to
+be shipped in a .deb or .rpm package:
+
+    app.GenerateManPages("man/")
+
+The pages are named after the command path, e.g. app.1, app-remote.1, app-remote-add.1, and are built from the
+same data as the help messages: the descriptions (LongDesc if specified), the spec string, and the options and
+arguments with their environment variables and default values.
+
+
+
+Reference Documentation
+
+GenerateMarkdownDocs and GenerateHTMLDocs write a reference page for the app and for each one of its visible
+commands into a directory:
+
+    app.GenerateMarkdownDocs("docs/")
+    app.GenerateHTMLDocs("site/")
+
+Each page contains the command usage line, its description, its arguments and options with their environment
+variables and default values, and links to the sub commands pages. The pages are named after the command path, e.g.
+app.md, app-remote.md, app-remote-add.md. The output only depends on the app configuration, and can thus be checked
+into version control and diffed in reviews.
+
+
+
+Suggestions
+
+When the user mistypes a command or an option name, the error message is followed by the closest matches
+among the visible sub commands and the options of the current command.
+A mistyped short option, e.g. -V, is matched against the short options regardless of their case, and against the long
+options without a short name starting with the same letter.
+Commands are only suggested for the arg found where a command was expected:
+
+    $ app stauts
+    Error: unexpected argument stauts, expected COMMAND after `app`
+    Did you mean "status" instead of "stauts"?
+
+SuggestionsDistance sets the maximum edit distance for a name to be suggested (2 by default),
+and DisableSuggestions turns the suggestions off.
+Both settings are inherited by the commands declared after they are set:
+
+    app := cli.App("app", "")
+    app.SuggestionsDistance = 1
+
+
+
+Usage Errors
+
+When the ErrorHandling policy is flag.ContinueOnError, Run returns the usage error,
+which can be inspected with errors.As.
+All of the error types below carry the command path (e.g. "app remote add") and the index of the offending arg in the args passed to Run:
+
+UnknownOptionError: an option which is not declared by the command was used
+
+MissingArgumentError: the args ended while the command expected more options or arguments
+
+UnexpectedArgumentError: an arg could not be matched by the command spec
+
+Both MissingArgumentError and UnexpectedArgumentError list the options and arguments which were expected
+at the furthest position the parser could reach:
+
+    $ cp -f
+    Error: expected --recursive or SRC after `cp -f`
+
+InvalidValueError: an option or an argument rejected its value, e.g. "abc" for an IntOpt.
+It wraps the error returned by the value's Set method
+
+    var invalid *cli.InvalidValueError
+    if errors.As(err, &invalid) {
+        log.Printf("bad value %q for %s", invalid.Value, invalid.Name)
+    }
+
+
+
+Error Returning Actions
+
+ActionE, BeforeE and AfterE are variants of Action, Before and After which receive a context and return an error.
+A returned error stops the execution like a panic would: the remaining Before interceptors and the action are skipped,
+the After interceptors of the parent commands still run, and Run returns the error:
+
+    app.Command("fetch", "Fetch the data", func(cmd *cli.Cmd) {
+        cmd.ActionE = func(ctx context.Context) error {
+            return fetch(ctx)
+        }
+    })
+
+    if err := app.RunContext(ctx, os.Args); err != nil {
+        log.Fatal(err)
+    }
+
+If an After interceptor also returns an error, Run still returns the first one.
+When both a plain function and its error returning variant are set, e.g. Action and ActionE, both are called, the plain one first.
+
+Run passes context.Background() to these functions, while RunContext passes the provided context.
+
+
+
+Signals
+
+When HandleSignals is set, the first SIGINT or SIGTERM cancels the context returned by Context(),
+which is also the one passed to ActionE, BeforeE and AfterE, leaving the command a chance to stop cleanly and the After interceptors a chance to run.
+A second signal exits the app immediately with SignalExitCode (130 by default):
+
+    app.HandleSignals = true
+
+    app.Command("serve", "Serve the app", func(cmd *cli.Cmd) {
+        cmd.Action = func() {
+            <-cmd.Context().Done()
+        }
+    })
+
+
+
+Choices
+
+The Choices field of StringOpt, StringsOpt, StringArg and StringsArg restricts the values accepted by an option or an argument.
+Any other value given in the command line is rejected with an InvalidValueError, and env vars with such a value are ignored:
+
+    format := app.String(cli.StringOpt{
+        Name:    "f format",
+        Value:   "json",
+        Choices: []string{"json", "yaml", "table"},
+        Desc:    "Output format",
+    })
+
+The choices are listed in the help message, e.g. `Output format (one of: json, yaml, table) (default "json")`,
+and are used as the shell completion candidates unless a Complete function is set.
+
+
+
+Config Files
+
+Besides the env vars, options and arguments can be filled from a config file using LoadConfig.
+JSON is supported out of the box, and other formats (TOML, YAML, INI, ...) can be plugged in by implementing the ConfigDecoder interface:
+
+    app := cli.App("app", "")
+    verbose := app.BoolOpt("v verbose", false, "Verbose mode")
+
+    app.Command("remote", "Manage remotes", func(cmd *cli.Cmd) {
+        cmd.Command("add", "Add a remote", func(cmd *cli.Cmd) {
+            force := cmd.BoolOpt("f force", false, "Overwrite an existing remote")
+        })
+    })
+
+    if err := app.LoadConfig("config.json", cli.JSONConfig); err != nil {
+        log.Fatal(err)
+    }
+
+The values are looked up using the command path without the app name followed by the option long name
+(or the lowercased argument name), either as nested objects or as dotted keys:
+
+    {
+        "verbose": true,
+        "remote": {"add": {"force": true}},
+        "remote.add.force": true
+    }
+
+Lists fill the slice options and objects the map options.
+A value from the config file takes precedence over the default value, but is overridden by an env var,
+which is itself overridden by the call arguments.
+Like env vars, an option filled from the config file doesn't need to be provided in the call arguments,
+and invalid values are ignored.
+
+
+
+Value Sources
+
+SourceOf tells where the value of an option or an argument came from: its default value, an env var,
+the config file or the call arguments, with the env var name, the config key or the argument index:
+
+    app.Action = func() {
+        log.Printf("verbose: %v (from %s)", *verbose, app.SourceOf("verbose"))
+    }
+
+which logs for example `verbose: true (from env $VERBOSE)`.
+
+
+
+Printing the Effective Configuration
+
+PrintConfigOption adds a hidden option which, when given anywhere before a `--` in the call arguments, prints the options and arguments
+of the matched command with their resolved values and sources instead of running it:
+
+    app.PrintConfigOption("print-config")
+
+    $ app remote add origin --print-config
+    NAME     VALUE     SOURCE
+    --force  false     default
+    --token  ******    env $TOKEN
+    NAME     "origin"  command line (argument 3)
+
+`--print-config=json` prints the same information as a JSON array.
+The values of the options and arguments with HideValue set are masked.
+The configuration is printed before the required options, the constraints and the Validate function are checked.
+
+
+
+Secrets
+
+Set the Secret field of StringOpt or StringArg for passwords, tokens and other secrets:
+
+    token := app.String(cli.StringOpt{
+        Name:   "token",
+        EnvVar: "API_TOKEN",
+        Desc:   "The API token",
+        Secret: true,
+    })
+
+The value of a secret is never shown in the help messages nor in the config dumps.
+If one of its env vars, e.g. API_TOKEN, is not set, the value is read from the file named by the same env var suffixed with _FILE,
+e.g. API_TOKEN_FILE=/run/secrets/token, with the trailing newlines stripped.
+
+
+
+Counting Options
+
+CountOpt defines an option counting its occurrences, e.g. for a verbosity level:
+
+    verbosity := app.CountOpt("v verbose", 0, "Increase the verbosity")
+
+Every occurrence increments the count, including in folded short options: `-v -v`, `-vv` and `-vfv` all count 2.
+`--verbose=3` adds 3, and an env var holding a number, e.g. VERBOSITY=2, sets the initial count.
+
+
+
+Negatable Options
+
+A bool option can declare itself Negatable, in which case its long names can also be prefixed with no- to set it to false:
+
+    color := app.Bool(cli.BoolOpt{
+        Name:      "c color",
+        Value:     true,
+        Desc:      "Colorize the output",
+        Negatable: true,
+    })
+
+Here, `--no-color` sets the option to false, while `--color` and `-c` still set it to true.
+The help message shows such an option as `-c, --[no-]color`.
+
+
+
+Optional Option Values
+
+The StringOpt, IntOpt, Float64Opt, DurationOpt and VarOpt options can make their value optional by setting NoOptDefVal,
+the value used when the option is given without one:
+
+    color := app.String(cli.StringOpt{
+        Name:        "c color",
+        Value:       "auto",
+        Desc:        "When to colorize the output",
+        NoOptDefVal: "always",
+    })
+
+Here, `--color` and `-c` set the option to always, while `--color=never`, `-c=never` and `-cnever` set it to never.
+The value must be attached to the option: in `--color never`, never is not the option's value but the next argument.
+The help message shows such an option as `-c, --color[=COLOR]`.
+
+
+
+Abbreviations
+
+Setting the app's AllowAbbreviations field lets the users abbreviate the long options and the sub commands names to any unique prefix:
+
+    app := cli.App("app", "")
+    app.AllowAbbreviations = true
+    app.BoolOpt("verbose", false, "")
+    app.BoolOpt("version", false, "")
+    app.Command("remote", "", ...)
+
+Here, `app --verb rem` is the same as `app --verbose remote`.
+A sub command abbreviation is only recognized where the command's options and arguments end, so an option value like `prod` in `app --env prod production` is never mistaken for one.
+An exact name always wins over a prefix, and an ambiguous prefix, e.g. `--ver`, is rejected with an AmbiguousAbbreviationError listing the candidates:
+
+    Error: ambiguous abbreviation --ver, could be --verbose or --version
+
+
+
+Constraints
+
+Instead of writing a spec for them, some common rules between the options and arguments can be declared:
+
+    cmd.MutuallyExclusive("file", "url", "stdin")
+    cmd.RequiredTogether("user", "password")
+    cmd.RequiredIf("tls-key", "tls-cert")
+
+Here, at most one of `--file`, `--url` and `--stdin` can be used, `--user` and `--password` must be used both or not at all,
+and `--tls-key` is required when `--tls-cert` is used.
+When one of the options must be used, ExactlyOne replaces MutuallyExclusive:
+
+    cmd.ExactlyOne("file", "url", "stdin")
+
+An option or argument counts as used when its value comes from the call arguments, an env var or a config file.
+
+The rules are checked after the call arguments were parsed, and a broken one is reported like any other usage error,
+with a MutuallyExclusiveError, an ExactlyOneError, a RequiredTogetherError or a RequiredIfError naming the offending options:
+
+    Error: --file and --url cannot be used together
+
+The rules are also listed in the help message, in a Constraints section.
+
+
+
+Required Options
+
+An option, other than a BoolOpt or a CountOpt, can be made mandatory without writing a spec by setting its Required field:
+
+    name := app.String(cli.StringOpt{
+        Name:     "n name",
+        EnvVar:   "NAME",
+        Required: true,
+    })
+
+The generated spec lists such options as mandatory before OPTIONS, e.g. `app --name=<name> [OPTIONS] SRC`.
+With a custom spec, the requirement is checked after parsing for the options only matched by OPTIONS,
+while an option explicitly written in the spec, e.g. `[-n]`, is only as mandatory as the spec makes it.
+A value coming from an env var or a config file satisfies the requirement; otherwise the call is rejected with a MissingOptionError:
+
+    Error: missing required option --name
+
+The help message and the generated docs mark such options as (required).
+
+
+
+Validation
+
+Every option and argument struct has a Validate field, a function receiving the final value
+(or the flag.Value itself for VarOpt and VarArg):
+
+    port := app.Int(cli.IntOpt{
+        Name:   "p port",
+        EnvVar: "PORT",
+        Value:  80,
+        Validate: func(port int) error {
+            if port < 1 || port > 65535 {
+                return fmt.Errorf("must be between 1 and 65535")
+            }
+            return nil
+        },
+    })
+
+The function is called when the value is set from the call arguments, an env var or a config file, but not for the initial value.
+An error makes the call fail with an InvalidValueError naming the option or argument, before the Before and Action functions get called:
+
+    Error: invalid value "0" for --port: must be between 1 and 65535
+
+
+
+Command Validation
+
+The checks involving several options or arguments can be done in the command's Validate function:
+
+    app.Command("report", "", func(cmd *cli.Cmd) {
+        since := cmd.IntOpt("since", 0, "")
+        until := cmd.IntOpt("until", 0, "")
+
+        cmd.Validate = func() error {
+            if *since > *until {
+                return fmt.Errorf("--since must be before --until")
+            }
+            return nil
+        }
+    })
+
+Validate is called once the call arguments were parsed, before any Before or Action function.
+A returned error is handled like a usage error: it is printed along with the command help, and the command's ErrorHandling applies.
+
+
+
+Introspection
+
+The command tree can be inspected from outside the package, e.g. to write a documentation generator or a linter:
+
+    func document(cmd cli.CommandInfo) {
+        fmt.Println(cmd.Path, "-", cmd.Desc)
+        for _, opt := range cmd.Options {
+            fmt.Println(" ", strings.Join(opt.Names, ", "), opt.Type, opt.Desc)
+        }
+        for _, arg := range cmd.Args {
+            fmt.Println(" ", arg.Name, arg.Type, arg.Desc)
+        }
+        for _, sub := range cmd.Commands {
+            if !sub.Hidden {
+                document(sub)
+            }
+        }
+    }
+
+    document(app.Info())
+
+The OptionInfo and ArgInfo values also tell whether an option is required or negatable, and list the accepted choices.
+Info, Commands, Options and Args initialize the commands first, so they can be called before Run.
+The returned values are copies: changing them has no effect on the app.
+
+
 */
 package cli
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/jawher/mow.cli/internal/container"
+)
+
+type docsWriter func(w io.Writer, c *Cmd)
+
+/*
+GenerateMarkdownDocs writes a Markdown reference page for the app and for each one of its visible commands into dir.
+
+The pages are named after the command path, e.g. the page of the "remote add" command of an app named "app"
+will be written to app-remote-add.md.
+The output only depends on the app configuration, which makes it suitable to be checked into version control.
+*/
+func (cli *Cli) GenerateMarkdownDocs(dir string) error {
+	return cli.generateDocs(dir, ".md", writeMarkdownPage)
+}
+
+/*
+GenerateHTMLDocs writes an HTML reference page for the app and for each one of its visible commands into dir.
+
+The pages are named after the command path, e.g. the page of the "remote add" command of an app named "app"
+will be written to app-remote-add.html.
+The output only depends on the app configuration, which makes it suitable to be checked into version control.
+*/
+func (cli *Cli) GenerateHTMLDocs(dir string) error {
+	return cli.generateDocs(dir, ".html", writeHTMLPage)
+}
+
+func (cli *Cli) generateDocs(dir, ext string, write docsWriter) error {
+	return cli.walk(func(c *Cmd) error {
+		buf := &bytes.Buffer{}
+		write(buf, c)
+
+		filename := filepath.Join(dir, c.pageName()+ext)
+		return ioutil.WriteFile(filename, buf.Bytes(), 0644)
+	})
+}
+
+// docsDesc returns the long description of the command if specified, or its short one otherwise
+func (c *Cmd) docsDesc() string {
+	if len(c.LongDesc) > 0 {
+		return strings.TrimSpace(c.LongDesc)
+	}
+	return strings.TrimSpace(c.desc)
+}
+
+func docsEnvVars(con *container.Container) []string {
+	var res []string
+	for _, v := range strings.Fields(envVarsForHelp(con)) {
+		res = append(res, "$"+v)
+	}
+	return res
+}
+
+func docsDefault(con *container.Container) string {
+	if con.HideValue {
+		return ""
+	}
+	return con.DefaultValue
+}
+
+func (c *Cmd) parentPageName() string {
+	return strings.Join(c.parents, "-")
+}
+
+func writeMarkdownPage(w io.Writer, c *Cmd) {
+	fmt.Fprintf(w, "# %s\n\n", c.path())
+
+	if desc := c.docsDesc(); desc != "" {
+		fmt.Fprintf(w, "%s\n\n", desc)
+	}
+
+	fmt.Fprint(w, "## Usage\n\n")
+	fmt.Fprintf(w, "```\n%s\n```\n", c.usage())
+
+	code := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return "`" + s + "`"
+	}
+	cell := func(s string) string {
+		s = strings.Replace(strings.TrimSpace(s), "|", `\|`, -1)
+		return strings.Replace(s, "\n", "<br>", -1)
+	}
+	writeParams := func(title, kind string, params []*container.Container, names func(*container.Container) []string) {
+		if len(params) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n## %s\n\n", title)
+		fmt.Fprintf(w, "| %s | Description | Environment | Default |\n", kind)
+		fmt.Fprint(w, "|---|---|---|---|\n")
+		for _, p := range params {
+			var ns, envs []string
+			for _, n := range names(p) {
+				ns = append(ns, code(n))
+			}
+			for _, e := range docsEnvVars(p) {
+				envs = append(envs, code(e))
+			}
+			fmt.Fprintf(w, "| %s | %s | %s | %s |\n", strings.Join(ns, ", "), cell(joinStrings(p.Desc, formatRequiredForHelp(p.Required), formatChoicesForHelp(p.Choices))), strings.Join(envs, ", "), cell(code(docsDefault(p))))
+		}
+	}
+
+	writeParams("Arguments", "Argument", c.args, func(con *container.Container) []string { return []string{con.Name} })
+	writeParams("Options", "Option", c.options, func(con *container.Container) []string {
+		return append(append([]string{}, con.Names...), con.NegatedNames()...)
+	})
+
+	if commands := c.visibleCommands(); len(commands) > 0 {
+		fmt.Fprint(w, "\n## Commands\n\n")
+		fmt.Fprint(w, "| Command | Description |\n")
+		fmt.Fprint(w, "|---|---|\n")
+		for _, sub := range commands {
+			fmt.Fprintf(w, "| [%s](%s.md) | %s |\n", code(strings.Join(sub.aliases, ", ")), sub.pageName(), cell(sub.desc))
+		}
+	}
+
+	if len(c.parents) > 0 {
+		fmt.Fprint(w, "\n## See also\n\n")
+		fmt.Fprintf(w, "* [%s](%s.md)\n", strings.Join(c.parents, " "), c.parentPageName())
+	}
+}
+
+func writeHTMLPage(w io.Writer, c *Cmd) {
+	esc := html.EscapeString
+	text := func(s string) string {
+		return strings.Replace(esc(strings.TrimSpace(s)), "\n", "<br>\n", -1)
+	}
+
+	fmt.Fprint(w, "<!DOCTYPE html>\n")
+	fmt.Fprint(w, "<html>\n")
+	fmt.Fprint(w, "<head>\n")
+	fmt.Fprint(w, "<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(w, "<title>%s</title>\n", esc(c.path()))
+	fmt.Fprint(w, "</head>\n")
+	fmt.Fprint(w, "<body>\n")
+	fmt.Fprintf(w, "<h1>%s</h1>\n", esc(c.path()))
+
+	if desc := c.docsDesc(); desc != "" {
+		fmt.Fprintf(w, "<p>%s</p>\n", text(desc))
+	}
+
+	fmt.Fprint(w, "<h2>Usage</h2>\n")
+	fmt.Fprintf(w, "<pre><code>%s</code></pre>\n", esc(c.usage()))
+
+	code := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return "<code>" + esc(s) + "</code>"
+	}
+	writeParams := func(title, kind string, params []*container.Container, names func(*container.Container) []string) {
+		if len(params) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "<h2>%s</h2>\n", title)
+		fmt.Fprint(w, "<table>\n")
+		fmt.Fprintf(w, "<tr><th>%s</th><th>Description</th><th>Environment</th><th>Default</th></tr>\n", kind)
+		for _, p := range params {
+			var ns, envs []string
+			for _, n := range names(p) {
+				ns = append(ns, code(n))
+			}
+			for _, e := range docsEnvVars(p) {
+				envs = append(envs, code(e))
+			}
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n", strings.Join(ns, ", "), text(joinStrings(p.Desc, formatRequiredForHelp(p.Required), formatChoicesForHelp(p.Choices))), strings.Join(envs, ", "), code(docsDefault(p)))
+		}
+		fmt.Fprint(w, "</table>\n")
+	}
+
+	writeParams("Arguments", "Argument", c.args, func(con *container.Container) []string { return []string{con.Name} })
+	writeParams("Options", "Option", c.options, func(con *container.Container) []string {
+		return append(append([]string{}, con.Names...), con.NegatedNames()...)
+	})
+
+	if commands := c.visibleCommands(); len(commands) > 0 {
+		fmt.Fprint(w, "<h2>Commands</h2>\n")
+		fmt.Fprint(w, "<table>\n")
+		fmt.Fprint(w, "<tr><th>Command</th><th>Description</th></tr>\n")
+		for _, sub := range commands {
+			fmt.Fprintf(w, "<tr><td><a href=\"%s.html\">%s</a></td><td>%s</td></tr>\n", esc(sub.pageName()), code(strings.Join(sub.aliases, ", ")), text(sub.desc))
+		}
+		fmt.Fprint(w, "</table>\n")
+	}
+
+	if len(c.parents) > 0 {
+		fmt.Fprint(w, "<h2>See also</h2>\n")
+		fmt.Fprintf(w, "<ul><li><a href=\"%s.html\">%s</a></li></ul>\n", esc(c.parentPageName()), esc(strings.Join(c.parents, " ")))
+	}
+
+	fmt.Fprint(w, "</body>\n")
+	fmt.Fprint(w, "</html>\n")
+}
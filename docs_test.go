@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDocs(t *testing.T) {
+	cases := []struct {
+		ext      string
+		generate func(app *Cli, dir string) error
+	}{
+		{ext: ".md", generate: (*Cli).GenerateMarkdownDocs},
+		{ext: ".html", generate: (*Cli).GenerateHTMLDocs},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(cas.ext, func(t *testing.T) {
+			app := referenceTestApp()
+
+			dir, err := ioutil.TempDir("", "mow-docs")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			require.NoError(t,
+				cas.generate(app, dir))
+
+			files, err := filepath.Glob(filepath.Join(dir, "*"))
+			require.NoError(t, err)
+
+			var names []string
+			for _, f := range files {
+				names = append(names, filepath.Base(f))
+			}
+			sort.Strings(names)
+			require.Equal(t, []string{"app-remote-add" + cas.ext, "app-remote" + cas.ext, "app" + cas.ext}, names)
+
+			for _, name := range names {
+				actual, err := ioutil.ReadFile(filepath.Join(dir, name))
+				require.NoError(t, err)
+
+				filename := filepath.Join("testdata", "docs", name)
+
+				if *genGolden {
+					require.NoError(t,
+						ioutil.WriteFile(filename, actual, 0644))
+				}
+
+				expected, err := ioutil.ReadFile(filename)
+				require.NoError(t, err, "Failed to read the expected documentation from %s", filename)
+
+				require.Equal(t, string(expected), string(actual))
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jawher/mow.cli/internal/container"
+	"github.com/jawher/mow.cli/internal/fsm"
+	"github.com/jawher/mow.cli/internal/lexer"
+)
+
+const (
+	dumpText = "text"
+	dumpJSON = "json"
+
+	maskedValue = "******"
+)
+
+/*
+PrintConfigOption adds a hidden option to the app named `name` (e.g. `print-config`) which, when given anywhere before a `--` in the call arguments,
+prints the options and arguments of the matched command with their resolved values and sources instead of running it:
+
+	$ app remote add origin --print-config
+	NAME     VALUE     SOURCE
+	--force  false     default
+	--token  ******    env $TOKEN
+	NAME     "origin"  command line (argument 3)
+
+The output is a JSON array instead with `--print-config=json`.
+The values of the options and arguments with HideValue set are masked.
+The configuration is printed even if a required option is missing, a constraint is broken or the command's Validate function would fail.
+*/
+func (cli *Cli) PrintConfigOption(name string) {
+	cli.dumpNames = mkOptStrs(name)
+}
+
+// dumpRequested looks for the print config option in args before a --, and returns the requested format and the option's position
+func (cli *Cli) dumpRequested(args []string) (string, int, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		kv := strings.SplitN(arg, "=", 2)
+		for _, name := range cli.dumpNames {
+			if kv[0] != name {
+				continue
+			}
+			if len(kv) == 1 {
+				return dumpText, i, true
+			}
+			if kv[1] == dumpText || kv[1] == dumpJSON {
+				return kv[1], i, true
+			}
+		}
+	}
+	return "", -1, false
+}
+
+// dumpFSM returns the fsm used to parse the args when printing the config,
+// which unlike the generated spec doesn't require the required options to be set
+func (c *Cmd) dumpFSM() *fsm.State {
+	if !c.specGenerated {
+		return c.fsm
+	}
+	spec := c.generatedSpec(false)
+	tokens, err := lexer.Tokenize(spec)
+	if err != nil {
+		panic(err)
+	}
+	s, err := c.compileSpec(spec, tokens)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// argIndex returns the index in the args passed to Run of the arg at pos in the args of the command starting at offset,
+// taking into account the print config option which was removed from them
+func (c *Cmd) argIndex(offset, pos int) int {
+	i := offset + pos
+	if c.dumpFormat != "" && i >= c.dumpPos {
+		i++
+	}
+	return i
+}
+
+type dumpEntry struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Source     string `json:"source"`
+	EnvVar     string `json:"envVar,omitempty"`
+	ConfigPath string `json:"configPath,omitempty"`
+	ConfigKey  string `json:"configKey,omitempty"`
+	Index      int    `json:"index,omitempty"`
+}
+
+// printConfig prints the options and arguments of the command with their values and sources in the requested format
+func (c *Cmd) printConfig(w io.Writer) {
+	cons := append(append([]*container.Container{}, c.options...), c.args...)
+
+	if c.dumpFormat == dumpJSON {
+		entries := make([]dumpEntry, 0, len(cons))
+		for _, con := range cons {
+			source := c.SourceOf(containerName(con))
+			entries = append(entries, dumpEntry{
+				Name:       containerName(con),
+				Value:      dumpValue(con),
+				Source:     source.Kind.String(),
+				EnvVar:     source.EnvVar,
+				ConfigPath: source.ConfigPath,
+				ConfigKey:  source.ConfigKey,
+				Index:      source.Index,
+			})
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprint(tw, "NAME\tVALUE\tSOURCE\n")
+	for _, con := range cons {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", containerName(con), dumpValue(con), c.SourceOf(containerName(con)))
+	}
+	tw.Flush()
+}
+
+func dumpValue(con *container.Container) string {
+	if con.HideValue {
+		return maskedValue
+	}
+	return con.Value.String()
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintConfig(t *testing.T) {
+	defer setAndRestoreEnv(map[string]string{"DUMP_VERBOSE": "true"})()
+
+	var out string
+	defer captureAndRestoreOutput(&out, nil)()
+
+	exitCalled := false
+	defer exitShouldBeCalledWith(t, 0, &exitCalled)()
+
+	app := App("app", "")
+	app.PrintConfigOption("print-config")
+	app.Spec = "[-v]"
+	app.Bool(BoolOpt{Name: "v verbose", EnvVar: "DUMP_VERBOSE"})
+	app.Action = func() {
+		t.Errorf("action should not have been called")
+	}
+
+	require.NoError(t, app.Run([]string{"app", "--print-config"}))
+	require.True(t, exitCalled, "exit should have been called")
+	require.Equal(t, `NAME       VALUE  SOURCE
+--verbose  true   env $DUMP_VERBOSE
+`, out)
+}
+
+func TestPrintConfigSubCommand(t *testing.T) {
+	var out string
+	defer captureAndRestoreOutput(&out, nil)()
+
+	exitCalled := false
+	defer exitShouldBeCalledWith(t, 0, &exitCalled)()
+
+	app := App("app", "")
+	app.PrintConfigOption("print-config")
+	app.Spec = "[-v]"
+	app.BoolOpt("v verbose", false, "")
+	app.Command("remote", "", func(cmd *Cmd) {
+		cmd.Command("add", "", func(cmd *Cmd) {
+			cmd.Spec = "[-f] [--token] NAME"
+			cmd.BoolOpt("f force", false, "")
+			cmd.String(StringOpt{Name: "token", Value: "secret", HideValue: true})
+			cmd.StringArg("NAME", "", "")
+			cmd.Action = func() {
+				t.Errorf("action should not have been called")
+			}
+		})
+	})
+
+	require.NoError(t, app.Run([]string{"app", "-v", "remote", "add", "origin", "--print-config"}))
+	require.True(t, exitCalled, "exit should have been called")
+	require.Equal(t, `NAME     VALUE     SOURCE
+--force  false     default
+--token  ******    default
+NAME     "origin"  command line (argument 4)
+`, out)
+}
+
+func TestPrintConfigJSON(t *testing.T) {
+	var out string
+	defer captureAndRestoreOutput(&out, nil)()
+
+	exitCalled := false
+	defer exitShouldBeCalledWith(t, 0, &exitCalled)()
+
+	app := App("app", "")
+	app.PrintConfigOption("print-config")
+	app.Command("add", "", func(cmd *Cmd) {
+		cmd.Spec = "[-f] NAME"
+		cmd.BoolOpt("f force", false, "")
+		cmd.StringArg("NAME", "", "")
+		cmd.Action = func() {
+			t.Errorf("action should not have been called")
+		}
+	})
+
+	require.NoError(t, app.Run([]string{"app", "add", "-f", "origin", "--print-config=json"}))
+	require.True(t, exitCalled, "exit should have been called")
+	require.Equal(t, `[
+  {
+    "name": "--force",
+    "value": "true",
+    "source": "command line",
+    "index": 2
+  },
+  {
+    "name": "NAME",
+    "value": "\"origin\"",
+    "source": "command line",
+    "index": 3
+  }
+]
+`, out)
+}
+
+func TestPrintConfigBeforeOtherArgs(t *testing.T) {
+	var out string
+	defer captureAndRestoreOutput(&out, nil)()
+
+	exitCalled := false
+	defer exitShouldBeCalledWith(t, 0, &exitCalled)()
+
+	app := App("app", "")
+	app.PrintConfigOption("print-config")
+	app.Command("remote", "", func(cmd *Cmd) {
+		cmd.Command("add", "", func(cmd *Cmd) {
+			cmd.StringArg("NAME", "", "")
+			cmd.Action = func() {
+				t.Errorf("action should not have been called")
+			}
+		})
+	})
+
+	require.NoError(t, app.Run([]string{"app", "--print-config", "remote", "add", "origin"}))
+	require.True(t, exitCalled, "exit should have been called")
+	require.Equal(t, `NAME  VALUE     SOURCE
+NAME  "origin"  command line (argument 4)
+`, out)
+}
+
+func TestPrintConfigMissingRequiredOption(t *testing.T) {
+	var out string
+	defer captureAndRestoreOutput(&out, nil)()
+
+	exitCalled := false
+	defer exitShouldBeCalledWith(t, 0, &exitCalled)()
+
+	app := App("app", "")
+	app.PrintConfigOption("print-config")
+	app.String(StringOpt{Name: "n name", Required: true})
+	app.Validate = func() error {
+		t.Errorf("validate should not have been called")
+		return nil
+	}
+	app.Action = func() {
+		t.Errorf("action should not have been called")
+	}
+
+	require.NoError(t, app.Run([]string{"app", "--print-config"}))
+	require.True(t, exitCalled, "exit should have been called")
+	require.Equal(t, `NAME    VALUE  SOURCE
+--name  ""     default
+`, out)
+}
+
+func TestPrintConfigUnknownFormat(t *testing.T) {
+	var out string
+	defer captureAndRestoreOutput(&out, nil)()
+
+	exitCalled := false
+	defer exitShouldBeCalledWith(t, 2, &exitCalled)()
+
+	app := App("app", "")
+	app.PrintConfigOption("print-config")
+	app.Action = func() {
+		t.Errorf("action should not have been called")
+	}
+
+	require.Error(t, app.Run([]string{"app", "--print-config=xml"}))
+	require.True(t, exitCalled, "exit should have been called")
+	require.Empty(t, out)
+}
+
+func TestPrintConfigNotEnabled(t *testing.T) {
+	defer suppressOutput()()
+
+	exitCalled := false
+	defer exitShouldBeCalledWith(t, 2, &exitCalled)()
+
+	app := App("app", "")
+	app.Action = func() {
+		t.Errorf("action should not have been called")
+	}
+
+	require.Error(t, app.Run([]string{"app", "--print-config"}))
+	require.True(t, exitCalled)
+}
+
+func TestPrintConfigAfterOptionsEnd(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	app := App("app", "")
+	app.PrintConfigOption("print-config")
+	name := app.StringArg("NAME", "", "")
+
+	called := false
+	app.Action = func() {
+		called = true
+	}
+
+	require.NoError(t, app.Run([]string{"app", "--", "--print-config"}))
+	require.True(t, called)
+	require.Equal(t, "--print-config", *name)
+}
@@ -2,9 +2,304 @@ package cli
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jawher/mow.cli/internal/container"
+	"github.com/jawher/mow.cli/internal/fsm"
+	"github.com/jawher/mow.cli/internal/matcher"
+	"github.com/jawher/mow.cli/internal/values"
 )
 
 var (
-	errHelpRequested    = errors.New("help requested")
-	errVersionRequested = errors.New("version requested")
+	errHelpRequested       = errors.New("help requested")
+	errVersionRequested    = errors.New("version requested")
+	errCompletionRequested = errors.New("completion requested")
+	errConfigPrinted       = errors.New("config printed")
 )
+
+/*
+UnknownOptionError is returned when an option which is not declared by the command is used
+*/
+type UnknownOptionError struct {
+	// The path of the command being parsed, e.g. "app remote add"
+	Command string
+	// The index of the offending arg in the args passed to Run
+	Index int
+	// The unknown option name, e.g. "--verbsoe"
+	Option string
+}
+
+func (e *UnknownOptionError) Error() string {
+	return fmt.Sprintf("unknown option %s", e.Option)
+}
+
+/*
+AmbiguousAbbreviationError is returned when the app allows abbreviations and an arg is the prefix of several long options or sub commands
+*/
+type AmbiguousAbbreviationError struct {
+	// The path of the command being parsed, e.g. "app remote add"
+	Command string
+	// The index of the offending arg in the args passed to Run
+	Index int
+	// The ambiguous abbreviation, e.g. "--ver"
+	Abbreviation string
+	// The long option names or sub command aliases starting with the abbreviation, e.g. "--verbose" and "--version"
+	Candidates []string
+}
+
+func (e *AmbiguousAbbreviationError) Error() string {
+	return fmt.Sprintf("ambiguous abbreviation %s, could be %s", e.Abbreviation, formatAlternatives(e.Candidates))
+}
+
+/*
+MissingArgumentError is returned when the args end while the command expects more options or arguments
+*/
+type MissingArgumentError struct {
+	// The path of the command being parsed, e.g. "app remote add"
+	Command string
+	// The index in the args passed to Run at which more args were expected, i.e. len(args)
+	Index int
+	// The args of the command which were matched before the failure
+	Matched []string
+	// The options and arguments which could have been used instead, e.g. SRC or --recursive
+	Expected []string
+}
+
+func (e *MissingArgumentError) Error() string {
+	if len(e.Expected) == 0 {
+		return "missing arguments"
+	}
+	return expectedAfter(e.Expected, e.Command, e.Matched)
+}
+
+/*
+UnexpectedArgumentError is returned when an arg cannot be matched by the command spec
+*/
+type UnexpectedArgumentError struct {
+	// The path of the command being parsed, e.g. "app remote add"
+	Command string
+	// The index of the offending arg in the args passed to Run
+	Index int
+	// The offending arg
+	Arg string
+	// The args of the command which were matched before the failure
+	Matched []string
+	// The options and arguments which could have been used instead, e.g. SRC or --recursive
+	Expected []string
+}
+
+func (e *UnexpectedArgumentError) Error() string {
+	if len(e.Expected) == 0 {
+		return fmt.Sprintf("unexpected argument %s", e.Arg)
+	}
+	return fmt.Sprintf("unexpected argument %s, %s", e.Arg, expectedAfter(e.Expected, e.Command, e.Matched))
+}
+
+// expectedAfter formats the expected options and arguments, e.g. "expected SRC or --recursive after `cp -f`"
+func expectedAfter(expected []string, command string, matched []string) string {
+	return fmt.Sprintf("expected %s after `%s`", formatAlternatives(expected), strings.Join(append([]string{command}, matched...), " "))
+}
+
+// formatAlternatives formats a list of alternatives, e.g. "SRC, DST or --recursive"
+func formatAlternatives(alternatives []string) string {
+	res := alternatives[len(alternatives)-1]
+	if len(alternatives) > 1 {
+		res = strings.Join(alternatives[:len(alternatives)-1], ", ") + " or " + res
+	}
+	return res
+}
+
+/*
+MissingOptionError is returned when an option declared as Required is set neither in the call arguments, nor from an env var or a config file
+*/
+type MissingOptionError struct {
+	// The path of the command being parsed, e.g. "app remote add"
+	Command string
+	// The missing option name, e.g. "--name"
+	Option string
+}
+
+func (e *MissingOptionError) Error() string {
+	return fmt.Sprintf("missing required option %s", e.Option)
+}
+
+/*
+MutuallyExclusiveError is returned when several of the options or arguments declared with Cmd.MutuallyExclusive are set
+*/
+type MutuallyExclusiveError struct {
+	// The path of the command being parsed, e.g. "app remote add"
+	Command string
+	// The names of the conflicting options or arguments, e.g. "--file" and "--url"
+	Names []string
+}
+
+func (e *MutuallyExclusiveError) Error() string {
+	return fmt.Sprintf("%s cannot be used together", formatNames(e.Names))
+}
+
+/*
+ExactlyOneError is returned when none or several of the options or arguments declared with Cmd.ExactlyOne are set
+*/
+type ExactlyOneError struct {
+	// The path of the command being parsed, e.g. "app remote add"
+	Command string
+	// The names of all the options or arguments of the rule, e.g. "--file", "--url" and "--stdin"
+	Names []string
+	// The names of the options or arguments which are set, empty if none is
+	Set []string
+}
+
+func (e *ExactlyOneError) Error() string {
+	if len(e.Set) == 0 {
+		return fmt.Sprintf("exactly one of %s is required", formatNames(e.Names))
+	}
+	return fmt.Sprintf("%s cannot be used together", formatNames(e.Set))
+}
+
+/*
+RequiredTogetherError is returned when only some of the options or arguments declared with Cmd.RequiredTogether are set
+*/
+type RequiredTogetherError struct {
+	// The path of the command being parsed, e.g. "app remote add"
+	Command string
+	// The names of the options or arguments which are set, e.g. "--user"
+	Set []string
+	// The names of the options or arguments which are missing, e.g. "--password"
+	Missing []string
+}
+
+func (e *RequiredTogetherError) Error() string {
+	return fmt.Sprintf("%s must be used with %s", formatNames(e.Set), formatNames(e.Missing))
+}
+
+/*
+RequiredIfError is returned when an option or argument declared with Cmd.RequiredIf is missing while the one it depends on is set
+*/
+type RequiredIfError struct {
+	// The path of the command being parsed, e.g. "app remote add"
+	Command string
+	// The name of the missing option or argument, e.g. "--tls-key"
+	Name string
+	// The name of the option or argument which requires it, e.g. "--tls-cert"
+	IfName string
+}
+
+func (e *RequiredIfError) Error() string {
+	return fmt.Sprintf("%s is required with %s", e.Name, e.IfName)
+}
+
+/*
+InvalidValueError is returned when an option or argument rejects the value it was given, e.g. "abc" for an IntOpt.
+It wraps the error returned by the value's Set method
+*/
+type InvalidValueError struct {
+	// The path of the command being parsed, e.g. "app remote add"
+	Command string
+	// The index of the arg holding the value in the args passed to Run
+	Index int
+	// The option (e.g. "--count") or argument (e.g. "SRC") name
+	Name string
+	// The rejected value, masked as ****** for the secrets and the options and arguments with HideValue set
+	Value string
+	// The error returned by the value's Set method
+	Err error
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("invalid value %q for %s: %v", e.Value, e.Name, e.Err)
+}
+
+// Unwrap returns the error returned by the value's Set method
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// errorValue returns the value to report in an error for con, masked if con is a secret or its value is hidden
+func errorValue(con *container.Container, value string) string {
+	if con.Secret || con.HideValue {
+		return maskedValue
+	}
+	return value
+}
+
+// usageError converts an error returned by the command fsm into one of the exported error types
+func (c *Cmd) usageError(err error, args []string, offset int) error {
+	switch e := err.(type) {
+	case *fsm.ValueError:
+		index := -1
+		if e.Pos >= 0 {
+			index = c.argIndex(offset, e.Pos)
+		}
+		return &InvalidValueError{Command: c.path(), Index: index, Name: containerName(e.Container), Value: errorValue(e.Container, e.Value), Err: e.Err}
+	case *fsm.ParseError:
+		if opt := c.missingRequiredOption(args); opt != nil {
+			return &MissingOptionError{Command: c.path(), Option: containerName(opt)}
+		}
+		if e.Pos >= len(args) {
+			return &MissingArgumentError{Command: c.path(), Index: c.argIndex(offset, e.Pos), Matched: args, Expected: e.Expected}
+		}
+		err := c.unmatchedArgError(args, e.Pos, offset)
+		if unexpected, ok := err.(*UnexpectedArgumentError); ok {
+			unexpected.Matched = args[:e.Pos]
+			unexpected.Expected = e.Expected
+			if e.Terminal && c.hasVisibleCommands() {
+				unexpected.Expected = append(unexpected.Expected, "COMMAND")
+			}
+		}
+		return err
+	default:
+		return err
+	}
+}
+
+// unmatchedArgError returns the error describing why the arg at pos could not be matched
+func (c *Cmd) unmatchedArgError(args []string, pos, offset int) error {
+	arg := args[pos]
+	if !optionsEnded(args[:pos]) {
+		if candidates := c.abbreviationCandidates(arg); len(candidates) > 0 {
+			return &AmbiguousAbbreviationError{Command: c.path(), Index: c.argIndex(offset, pos), Abbreviation: strings.SplitN(arg, "=", 2)[0], Candidates: candidates}
+		}
+		if name := c.unknownOption(arg); name != "" {
+			return &UnknownOptionError{Command: c.path(), Index: c.argIndex(offset, pos), Option: name}
+		}
+	}
+	return &UnexpectedArgumentError{Command: c.path(), Index: c.argIndex(offset, pos), Arg: arg}
+}
+
+// unknownOption returns the first option name in arg which is not declared by the command, if any
+func (c *Cmd) unknownOption(arg string) string {
+	switch {
+	case arg == "-" || arg == "--" || !strings.HasPrefix(arg, "-"):
+		return ""
+	case strings.HasPrefix(arg, "--"):
+		name := strings.SplitN(arg, "=", 2)[0]
+		if _, opt := matcher.LookupLongOpt(c.optionsIdx, name, c.abbreviations); opt == nil {
+			return name
+		}
+		return ""
+	default:
+		for i := 1; i < len(arg); i++ {
+			opt, found := c.optionsIdx["-"+arg[i:i+1]]
+			if !found {
+				return "-" + arg[i:i+1]
+			}
+			if !values.IsBool(opt.Value) {
+				return ""
+			}
+		}
+		return ""
+	}
+}
+
+func containerName(con *container.Container) string {
+	for _, name := range con.Names {
+		if strings.HasPrefix(name, "--") {
+			return name
+		}
+	}
+	if len(con.Names) > 0 {
+		return con.Names[0]
+	}
+	return con.Name
+}
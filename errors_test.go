@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/jawher/mow.cli/internal/fsm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageErrors(t *testing.T) {
+	cases := []struct {
+		config   func(*Cli)
+		args     []string
+		expected error
+		message  string
+	}{
+		{
+			config: func(app *Cli) {
+				app.Command("cp", "", func(cmd *Cmd) {
+					cmd.Spec = "[-f] [-n=<count>] SRC... DST"
+					cmd.BoolOpt("f force", false, "")
+					cmd.IntOpt("n count", 0, "")
+					cmd.StringsArg("SRC", nil, "")
+					cmd.StringArg("DST", "", "")
+					cmd.Action = func() {}
+				})
+			},
+			args:     []string{"app", "cp", "--forc", "a", "b"},
+			expected: &UnknownOptionError{Command: "app cp", Index: 2, Option: "--forc"},
+			message:  "unknown option --forc",
+		},
+		{
+			config: func(app *Cli) {
+				app.Command("cp", "", func(cmd *Cmd) {
+					cmd.Spec = "[-f] [-n=<count>] SRC... DST"
+					cmd.BoolOpt("f force", false, "")
+					cmd.IntOpt("n count", 0, "")
+					cmd.StringsArg("SRC", nil, "")
+					cmd.StringArg("DST", "", "")
+					cmd.Action = func() {}
+				})
+			},
+			args:     []string{"app", "cp", "a", "-fx=1", "b"},
+			expected: &UnknownOptionError{Command: "app cp", Index: 3, Option: "-x"},
+			message:  "unknown option -x",
+		},
+		{
+			config: func(app *Cli) {
+				app.BoolOpt("v verbose", false, "")
+				app.Command("cp", "", func(cmd *Cmd) {
+					cmd.Spec = "[-f] [-n=<count>] SRC... DST"
+					cmd.BoolOpt("f force", false, "")
+					cmd.IntOpt("n count", 0, "")
+					cmd.StringsArg("SRC", nil, "")
+					cmd.StringArg("DST", "", "")
+					cmd.Action = func() {}
+				})
+			},
+			args:     []string{"app", "-v", "cp", "-f", "a"},
+			expected: &MissingArgumentError{Command: "app cp", Index: 5, Matched: []string{"-f", "a"}, Expected: []string{"SRC", "DST"}},
+			message:  "expected SRC or DST after `app cp -f a`",
+		},
+		{
+			config: func(app *Cli) {
+				app.Command("cp", "", func(cmd *Cmd) {
+					cmd.Spec = "[-f] [-n=<count>] SRC... DST"
+					cmd.BoolOpt("f force", false, "")
+					cmd.IntOpt("n count", 0, "")
+					cmd.StringsArg("SRC", nil, "")
+					cmd.StringArg("DST", "", "")
+					cmd.Action = func() {}
+				})
+			},
+			args:     []string{"app", "cp", "-f", "-f", "a", "b"},
+			expected: &UnexpectedArgumentError{Command: "app cp", Index: 3, Arg: "-f", Matched: []string{"-f"}, Expected: []string{"--count", "SRC"}},
+			message:  "unexpected argument -f, expected --count or SRC after `app cp -f`",
+		},
+		{
+			config: func(app *Cli) {
+				app.BoolOpt("v verbose", false, "")
+				app.Command("status", "", func(cmd *Cmd) {})
+			},
+			args:     []string{"app", "stauts"},
+			expected: &UnexpectedArgumentError{Command: "app", Index: 1, Arg: "stauts", Matched: []string{}, Expected: []string{"OPTIONS", "COMMAND"}},
+			message:  "unexpected argument stauts, expected OPTIONS or COMMAND after `app`",
+		},
+		{
+			config: func(app *Cli) {
+				app.Command("remote", "", func(cmd *Cmd) {
+					cmd.Command("add", "", func(cmd *Cmd) {
+						cmd.IntArg("PORT", 0, "")
+						cmd.Action = func() {}
+					})
+				})
+			},
+			args:     []string{"app", "remote", "add", "--", "80", "-x"},
+			expected: &UnexpectedArgumentError{Command: "app remote add", Index: 5, Arg: "-x", Matched: []string{"--", "80"}},
+			message:  "unexpected argument -x",
+		},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			defer suppressOutput()()
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+			cas.config(app)
+
+			err := app.Run(cas.args)
+			require.Equal(t, cas.expected, err)
+			require.EqualError(t, err, cas.message)
+		})
+	}
+}
+
+func TestUsageErrorDoesNotInitializeCommands(t *testing.T) {
+	app := App("app", "")
+	app.BoolOpt("v verbose", false, "")
+	inits := 0
+	app.Command("broken", "", func(cmd *Cmd) {
+		inits++
+		cmd.Spec = "[SRC"
+	})
+	require.NoError(t, app.doInit())
+
+	err := app.usageError(&fsm.ParseError{Pos: 0, Expected: []string{"OPTIONS"}, Terminal: true}, []string{"zzz"}, 1)
+	require.Equal(t, &UnexpectedArgumentError{Command: "app", Index: 1, Arg: "zzz", Matched: []string{}, Expected: []string{"OPTIONS", "COMMAND"}}, err)
+	require.Equal(t, 0, inits)
+}
+
+func TestInvalidValueError(t *testing.T) {
+	cases := []struct {
+		config   func(*Cli)
+		args     []string
+		index    int
+		name     string
+		value    string
+		command  string
+		expected string
+	}{
+		{
+			config: func(app *Cli) {
+				app.Command("cp", "", func(cmd *Cmd) {
+					cmd.Spec = "[-f] [-n=<count>] SRC... DST"
+					cmd.BoolOpt("f force", false, "")
+					cmd.IntOpt("n count", 0, "")
+					cmd.StringsArg("SRC", nil, "")
+					cmd.StringArg("DST", "", "")
+					cmd.Action = func() {}
+				})
+			},
+			args:     []string{"app", "cp", "-n", "abc", "a", "b"},
+			index:    3,
+			name:     "--count",
+			value:    "abc",
+			command:  "app cp",
+			expected: `invalid value "abc" for --count: strconv.ParseInt: parsing "abc": invalid syntax`,
+		},
+		{
+			config: func(app *Cli) {
+				app.Command("cp", "", func(cmd *Cmd) {
+					cmd.Spec = "[-f] [-n=<count>] SRC... DST"
+					cmd.BoolOpt("f force", false, "")
+					cmd.IntOpt("n count", 0, "")
+					cmd.StringsArg("SRC", nil, "")
+					cmd.StringArg("DST", "", "")
+					cmd.Action = func() {}
+				})
+			},
+			args:     []string{"app", "cp", "-fnx", "a", "b"},
+			index:    2,
+			name:     "--count",
+			value:    "x",
+			command:  "app cp",
+			expected: `invalid value "x" for --count: strconv.ParseInt: parsing "x": invalid syntax`,
+		},
+		{
+			config: func(app *Cli) {
+				app.BoolOpt("v verbose", false, "")
+				app.Command("remote", "", func(cmd *Cmd) {
+					cmd.Command("add", "", func(cmd *Cmd) {
+						cmd.IntArg("PORT", 0, "")
+						cmd.Action = func() {}
+					})
+				})
+			},
+			args:     []string{"app", "-v", "remote", "add", "http"},
+			index:    4,
+			name:     "PORT",
+			value:    "http",
+			command:  "app remote add",
+			expected: `invalid value "http" for PORT: strconv.ParseInt: parsing "http": invalid syntax`,
+		},
+		{
+			config: func(app *Cli) {
+				app.IntOpt("p port", 0, "")
+				app.BoolOpt("v verbose", false, "")
+				app.StringArg("SRC", "", "")
+				app.Action = func() {}
+			},
+			args:     []string{"app", "-p", "abc", "-v", "a"},
+			index:    2,
+			name:     "--port",
+			value:    "abc",
+			command:  "app",
+			expected: `invalid value "abc" for --port: strconv.ParseInt: parsing "abc": invalid syntax`,
+		},
+		{
+			config: func(app *Cli) {
+				app.BoolOpt("v verbose", false, "")
+				app.IntOpt("p port", 0, "")
+				app.StringArg("SRC", "", "")
+				app.Action = func() {}
+			},
+			args:     []string{"app", "-v", "--port=abc", "-v", "a"},
+			index:    2,
+			name:     "--port",
+			value:    "abc",
+			command:  "app",
+			expected: `invalid value "abc" for --port: strconv.ParseInt: parsing "abc": invalid syntax`,
+		},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			defer suppressOutput()()
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+			cas.config(app)
+
+			err := app.Run(cas.args)
+
+			var invalid *InvalidValueError
+			require.True(t, errors.As(err, &invalid))
+			require.Equal(t, cas.command, invalid.Command)
+			require.Equal(t, cas.index, invalid.Index)
+			require.Equal(t, cas.name, invalid.Name)
+			require.Equal(t, cas.value, invalid.Value)
+			require.EqualError(t, err, cas.expected)
+
+			var numErr *strconv.NumError
+			require.True(t, errors.As(err, &numErr), "the value error should be unwrapped")
+		})
+	}
+}
+
+func TestUsageErrorIsPrinted(t *testing.T) {
+	var stdErr string
+	defer captureAndRestoreOutput(nil, &stdErr)()
+
+	app := App("app", "")
+	app.ErrorHandling = flag.ContinueOnError
+	app.Command("cp", "", func(cmd *Cmd) {
+		cmd.BoolOpt("f force", false, "")
+		cmd.Action = func() {}
+	})
+
+	err := app.Run([]string{"app", "cp", "--forc"})
+
+	var unknown *UnknownOptionError
+	require.True(t, errors.As(err, &unknown))
+	require.Contains(t, stdErr, "Error: unknown option --forc\n")
+}
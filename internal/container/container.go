@@ -1,18 +1,79 @@
 package container
 
-import "flag"
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
 
 /*
 Container holds an option or an arg data
 */
 type Container struct {
-	Name            string
-	Desc            string
-	EnvVar          string
-	Names           []string
-	HideValue       bool
-	ValueSetFromEnv bool
-	ValueSetByUser  *bool
-	Value           flag.Value
-	DefaultValue    string
+	Name               string
+	Desc               string
+	EnvVar             string
+	Names              []string
+	HideValue          bool
+	Secret             bool
+	Negatable          bool
+	NoOptDefVal        string
+	Required           bool
+	Validate           func() error
+	ValueSetFromEnv    bool
+	ValueEnvVar        string
+	ValueSetFromConfig bool
+	ValueConfigKey     string
+	ValueSetFromArgs   bool
+	ValuePos           []int
+	ValueSetByUser     *bool
+	Value              flag.Value
+	DefaultValue       string
+	Complete           func(prefix string) []string
+	Choices            []string
+}
+
+/*
+ValueOptional returns true if the option can be passed without a value, in which case it is set to NoOptDefVal
+*/
+func (c *Container) ValueOptional() bool {
+	return c.NoOptDefVal != ""
+}
+
+/*
+NegatedNames returns the names which set a negatable option to false, i.e. its long names prefixed with no-, e.g. --no-color
+*/
+func (c *Container) NegatedNames() []string {
+	if !c.Negatable {
+		return nil
+	}
+	var res []string
+	for _, n := range c.Names {
+		if strings.HasPrefix(n, "--") {
+			res = append(res, "--no-"+n[2:])
+		}
+	}
+	return res
+}
+
+/*
+ValueSetExternally returns true if the value was set from an env var or a config file, i.e. it doesn't need to be provided in the call arguments
+*/
+func (c *Container) ValueSetExternally() bool {
+	return c.ValueSetFromEnv || c.ValueSetFromConfig
+}
+
+/*
+Check returns an error if the raw value v is not acceptable for this container, i.e. if it is not one of its choices
+*/
+func (c *Container) Check(v string) error {
+	if len(c.Choices) == 0 {
+		return nil
+	}
+	for _, choice := range c.Choices {
+		if v == choice {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(c.Choices, ", "))
 }
@@ -6,6 +6,14 @@ before/after listeners executed and finally that the app whould exit with the pr
 */
 type ExitCode int
 
+/*
+ReturnedError is a value used in a call to panic to signify that a step returned an error:
+code execution should be stopped, before/after listeners executed and finally the error returned by RunE
+*/
+type ReturnedError struct {
+	Err error
+}
+
 /*
 Step is the building block of execution flow.
 It has a code block to run, a success step to go to if the former succeeds, or go to an error step otherwise
@@ -46,6 +54,12 @@ func (s *Step) callDo(p interface{}) {
 	}
 	defer func() {
 		if e := recover(); e != nil {
+			if _, failing := p.(ReturnedError); failing {
+				if _, ok := e.(ReturnedError); ok {
+					// keep the first returned error, e.g. the one of an action rather than the one of the after step
+					e = p
+				}
+			}
 			if s.Error == nil {
 				panic(p)
 			}
@@ -54,3 +68,20 @@ func (s *Step) callDo(p interface{}) {
 	}()
 	s.Do()
 }
+
+/*
+RunE runs the step like Run, but returns the error carried by a ReturnedError panic instead of propagating it
+*/
+func (s *Step) RunE() (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			returned, ok := e.(ReturnedError)
+			if !ok {
+				panic(e)
+			}
+			err = returned.Err
+		}
+	}()
+	s.Run(nil)
+	return nil
+}
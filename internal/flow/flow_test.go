@@ -1,6 +1,8 @@
 package flow
 
 import (
+	"errors"
+
 	"github.com/stretchr/testify/require"
 
 	"testing"
@@ -105,3 +107,61 @@ func TestStepShouldNopIfNoSuccessNorPanic(t *testing.T) {
 
 	step.Run(nil)
 }
+
+func TestStepRunEReturnsTheReturnedError(t *testing.T) {
+	err := errors.New("failed")
+	calls := 0
+
+	step := &Step{
+		Do: func() {
+			calls++
+			panic(ReturnedError{Err: err})
+		},
+		Error: &Step{
+			Do: func() {
+				calls++
+			},
+		},
+	}
+
+	require.Equal(t, err, step.RunE())
+	require.Equal(t, 2, calls, "Both do and error should be called")
+}
+
+func TestStepRunEReturnsTheFirstReturnedError(t *testing.T) {
+	first := errors.New("first")
+
+	step := &Step{
+		Do: func() {
+			panic(ReturnedError{Err: first})
+		},
+		Error: &Step{
+			Do: func() {
+				panic(ReturnedError{Err: errors.New("second")})
+			},
+			Error: &Step{},
+		},
+	}
+
+	require.Equal(t, first, step.RunE())
+}
+
+func TestStepRunEReturnsNilOnSuccess(t *testing.T) {
+	step := &Step{Do: func() {}}
+
+	require.NoError(t, step.RunE())
+}
+
+func TestStepRunERethrowsOtherPanics(t *testing.T) {
+	defer func() {
+		require.Equal(t, 42, recover(), "should panicked with the same value")
+	}()
+
+	step := &Step{Do: func() {
+		panic(42)
+	}, Error: &Step{}}
+
+	step.RunE()
+
+	t.Fatalf("Should have panicked")
+}
@@ -116,33 +116,187 @@ func (s *State) has(tr *Transition) bool {
 	return false
 }
 
+/*
+ParseError is returned by Parse when the args cannot be matched by the FSM
+*/
+type ParseError struct {
+	// Pos is the index of the first arg which could not be consumed, or len(args) if more args were expected
+	Pos int
+	// Expected describes the matchers which were tried at Pos, e.g. SRC or --recursive
+	Expected []string
+	// Terminal is true if the args before Pos form a valid usage on their own
+	Terminal bool
+}
+
+func (e *ParseError) Error() string {
+	return "incorrect usage"
+}
+
+/*
+ValueError is returned by Parse when a matched value is rejected by its option or argument
+*/
+type ValueError struct {
+	Container *container.Container
+	Value     string
+	// Pos is the index of the arg holding the value
+	Pos int
+	Err error
+}
+
+func (e *ValueError) Error() string {
+	return e.Err.Error()
+}
+
 // Parse tries to navigate into the FSM according to the provided args
 func (s *State) Parse(args []string) error {
+	return s.parse(args, false)
+}
+
+// ParseAbbreviated is like Parse, but lets the long options be abbreviated to any unique prefix, e.g. --verb for --verbose
+func (s *State) ParseAbbreviated(args []string) error {
+	return s.parse(args, true)
+}
+
+func (s *State) parse(args []string, allowAbbreviations bool) error {
 	pc := matcher.NewParseContext()
-	ok := s.apply(args, pc)
+	pc.AllowAbbreviations = allowAbbreviations
+	pos := make([]int, len(args))
+	for i := range pos {
+		pos[i] = i
+	}
+	f := &failure{total: len(args), remaining: len(args) + 1}
+	ok := s.apply(args, pos, pc, f)
 	if !ok {
-		return fmt.Errorf("incorrect usage")
+		return &ParseError{Pos: f.pos, Expected: f.describe(), Terminal: f.terminal}
 	}
 
-	if err := fillContainers(pc.Opts); err != nil {
+	if err := fillContainers(pc.Opts, pc.Positions); err != nil {
 		return err
 	}
 
-	return fillContainers(pc.Args)
+	return fillContainers(pc.Args, pc.Positions)
+}
+
+// failure keeps track of the furthest position reached in the args while navigating the FSM,
+// and of the matchers which failed there
+type failure struct {
+	total     int
+	remaining int
+	pos       int
+	expected  []matcher.Matcher
+	terminal  bool
+}
+
+func (f *failure) describe() []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, m := range f.expected {
+		describer, ok := m.(matcher.Describer)
+		if !ok {
+			continue
+		}
+		desc := describer.Describe()
+		if desc == "" || seen[desc] {
+			continue
+		}
+		seen[desc] = true
+		res = append(res, desc)
+	}
+	return res
+}
+
+/*
+Completion is the result of navigating the FSM with a partial list of args
+*/
+type Completion struct {
+	// Candidates are the values proposed by the matchers which could consume the next arg
+	Candidates []string
+	// Consumed is true if all the args could be consumed
+	Consumed bool
+	// Terminal is true if the args form a valid usage on their own
+	Terminal bool
+}
+
+// Complete navigates the FSM according to the provided args and collects the candidates for a next arg starting with prefix
+func (s *State) Complete(args []string, prefix string) Completion {
+	res := &completion{prefix: prefix, visited: map[string]bool{}}
+	s.complete(args, matcher.NewParseContext(), res)
+	return res.Completion
 }
 
-func fillContainers(containers map[*container.Container][]string) error {
+type completion struct {
+	Completion
+	prefix  string
+	visited map[string]bool
+}
+
+func (s *State) complete(args []string, pc matcher.ParseContext, res *completion) {
+	key := fmt.Sprintf("%p %v %q", s, pc.RejectOptions, args)
+	if res.visited[key] {
+		return
+	}
+	res.visited[key] = true
+
+	if len(args) > 0 && !pc.RejectOptions && args[0] == "--" {
+		pc.RejectOptions = true
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		res.Consumed = true
+		if s.Terminal {
+			res.Terminal = true
+		}
+		for _, tr := range s.Transitions {
+			if completer, ok := tr.Matcher.(matcher.Completer); ok {
+				res.Candidates = append(res.Candidates, completer.Complete(res.prefix, &pc)...)
+			}
+		}
+	}
+
+	for _, tr := range s.Transitions {
+		fresh := matcher.NewParseContext()
+		fresh.RejectOptions = pc.RejectOptions
+		if ok, rem := tr.Matcher.Match(args, &fresh); ok {
+			tr.Next.complete(rem, fresh, res)
+		}
+	}
+}
+
+func fillContainers(containers map[*container.Container][]string, positions map[*container.Container][]int) error {
 	for con, vs := range containers {
 		if multiValued, ok := con.Value.(values.MultiValued); ok {
 			multiValued.Clear()
 		}
-		for _, v := range vs {
-			if err := con.Value.Set(v); err != nil {
-				return err
+		for i, v := range vs {
+			err := con.Check(v)
+			if err == nil {
+				err = con.Value.Set(v)
+			}
+			if err != nil {
+				pos := -1
+				if i < len(positions[con]) {
+					pos = positions[con][i]
+				}
+				return &ValueError{Container: con, Value: v, Pos: pos, Err: err}
+			}
+		}
+
+		if con.Validate != nil {
+			if err := con.Validate(); err != nil {
+				// report the last value, which completed the option or argument
+				pos := -1
+				if ps := positions[con]; len(ps) > 0 {
+					pos = ps[len(ps)-1]
+				}
+				return &ValueError{Container: con, Value: vs[len(vs)-1], Pos: pos, Err: err}
 			}
 		}
 
 		con.ValueSetFromEnv = false
+		con.ValueSetFromConfig = false
+		con.ValueSetFromArgs = true
+		con.ValuePos = positions[con]
 		if con.ValueSetByUser != nil {
 			*con.ValueSetByUser = true
 		}
@@ -150,7 +304,7 @@ func fillContainers(containers map[*container.Container][]string) error {
 	return nil
 }
 
-func (s *State) apply(args []string, pc matcher.ParseContext) bool {
+func (s *State) apply(args []string, pos []int, pc matcher.ParseContext, f *failure) bool {
 	if s.Terminal && len(args) == 0 {
 		return true
 	}
@@ -161,26 +315,46 @@ func (s *State) apply(args []string, pc matcher.ParseContext) bool {
 		if !pc.RejectOptions && arg == "--" {
 			pc.RejectOptions = true
 			args = args[1:]
+			pos = pos[1:]
 		}
 	}
 
+	if len(args) < f.remaining {
+		f.remaining = len(args)
+		f.pos = f.total
+		if len(pos) > 0 {
+			f.pos = pos[0]
+		}
+		f.expected = nil
+		f.terminal = false
+	}
+	if len(args) == f.remaining && s.Terminal {
+		f.terminal = true
+	}
+
 	type match struct {
-		tr  *Transition
-		rem []string
-		pc  matcher.ParseContext
+		tr     *Transition
+		rem    []string
+		remPos []int
+		pc     matcher.ParseContext
 	}
 
 	var matches []*match
 	for _, tr := range s.Transitions {
 		fresh := matcher.NewParseContext()
 		fresh.RejectOptions = pc.RejectOptions
+		fresh.AllowAbbreviations = pc.AllowAbbreviations
+		fresh.ArgPos = pos
 		if ok, rem := tr.Matcher.Match(args, &fresh); ok {
-			matches = append(matches, &match{tr, rem, fresh})
+			remPos := matcher.RemainingPositions(args, pos, rem)
+			matches = append(matches, &match{tr, rem, remPos, fresh})
+		} else if len(args) == f.remaining {
+			f.expected = append(f.expected, tr.Matcher)
 		}
 	}
 
 	for _, m := range matches {
-		if ok := m.tr.Next.apply(m.rem, m.pc); ok {
+		if ok := m.tr.Next.apply(m.rem, m.remPos, m.pc, f); ok {
 			pc.Merge(m.pc)
 			return true
 		}
@@ -0,0 +1,69 @@
+package fsm_test
+
+import (
+	"testing"
+
+	"github.com/jawher/mow.cli/internal/container"
+	"github.com/jawher/mow.cli/internal/fsm"
+	"github.com/jawher/mow.cli/internal/fsm/fsmtest"
+	"github.com/jawher/mow.cli/internal/matcher"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplete(t *testing.T) {
+	var (
+		aCon = &container.Container{
+			Name: "A",
+			Complete: func(prefix string) []string {
+				return []string{"a1", "a2", "-a"}
+			},
+		}
+		bCon = &container.Container{
+			Name: "B",
+			Complete: func(prefix string) []string {
+				return []string{"b1"}
+			},
+		}
+	)
+	matchers := map[string]matcher.Matcher{
+		"a": matcher.NewArg(aCon),
+		"b": matcher.NewArg(bCon),
+	}
+	s := fsmtest.NewFsm(`
+		S1 a S2
+		S2 b (S3)
+	`, matchers)
+
+	s.Prepare()
+
+	cases := []struct {
+		args     []string
+		expected fsm.Completion
+	}{
+		{nil, fsm.Completion{Candidates: []string{"a1", "a2"}, Consumed: true}},
+		{[]string{"--"}, fsm.Completion{Candidates: []string{"a1", "a2", "-a"}, Consumed: true}},
+		{[]string{"x"}, fsm.Completion{Candidates: []string{"b1"}, Consumed: true}},
+		{[]string{"x", "y"}, fsm.Completion{Consumed: true, Terminal: true}},
+		{[]string{"x", "y", "z"}, fsm.Completion{}},
+		{[]string{"-x"}, fsm.Completion{}},
+	}
+
+	for _, cas := range cases {
+		t.Logf("args %#v", cas.args)
+		require.Equal(t, cas.expected, s.Complete(cas.args, ""))
+	}
+}
+
+func TestCompleteDoesNotLoopForever(t *testing.T) {
+	matchers := map[string]matcher.Matcher{
+		"*": fsmtest.YepMatcher{},
+	}
+	s := fsmtest.NewFsm(`
+		S1 * S2
+		S2 * S1
+	`, matchers)
+
+	res := s.Complete([]string{"x"}, "")
+
+	require.False(t, res.Consumed)
+}
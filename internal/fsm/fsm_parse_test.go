@@ -65,10 +65,129 @@ func TestParseFillsContainers(t *testing.T) {
 	require.NoError(t, err)
 
 	require.False(t, boolCon.ValueSetFromEnv)
+	require.True(t, boolCon.ValueSetFromArgs)
 	require.True(t, boolSetByUser)
 	require.True(t, boolVar)
 
 	require.False(t, stringsCon.ValueSetFromEnv)
+	require.True(t, stringsCon.ValueSetFromArgs)
 	require.True(t, stringsSetByUser)
 	require.Equal(t, stringsVar, []string{"new", "value"})
 }
+
+func TestParseErrorPosition(t *testing.T) {
+	matchers := map[string]matcher.Matcher{
+		"a": fsmtest.TestMatcher{
+			MatchFunc: func(args []string, c *matcher.ParseContext) (bool, []string) {
+				if len(args) == 0 || args[0] != "a" {
+					return false, args
+				}
+				return true, args[1:]
+			},
+		},
+	}
+	s := fsmtest.NewFsm(`
+		S1 a S2
+		S2 a (S3)
+	`, matchers)
+
+	s.Prepare()
+
+	cases := []struct {
+		args []string
+		pos  int
+	}{
+		{[]string{"b"}, 0},
+		{[]string{"a", "b"}, 1},
+		{[]string{"a", "--", "b"}, 2},
+		{[]string{"a"}, 1},
+		{[]string{"a", "a", "a"}, 2},
+		{[]string{"a", "a", "--", "a"}, 3},
+	}
+
+	for _, cas := range cases {
+		err := s.Parse(cas.args)
+		parseErr, ok := err.(*fsm.ParseError)
+		require.True(t, ok, "args %q: expected a parse error, got %#v", cas.args, err)
+		require.Equal(t, cas.pos, parseErr.Pos, "args %q", cas.args)
+	}
+}
+
+func TestParseValueError(t *testing.T) {
+	var (
+		intVar int
+		intCon = &container.Container{
+			Value: values.NewInt(&intVar, 0),
+		}
+	)
+	matchers := map[string]matcher.Matcher{
+		"-": fsmtest.TestMatcher{
+			MatchFunc: func(args []string, c *matcher.ParseContext) (bool, []string) {
+				// consume the option and its value in the middle of the args
+				if len(args) < 3 || args[1] != "-n" {
+					return false, args
+				}
+				c.AddOpt(intCon, args[2], 2)
+				return true, append([]string{args[0]}, args[3:]...)
+			},
+		},
+		"x": fsmtest.TestMatcher{
+			MatchFunc: func(args []string, c *matcher.ParseContext) (bool, []string) {
+				return true, args[1:]
+			},
+		},
+	}
+	s := fsmtest.NewFsm(`
+		S1 - S2
+		S2 x S3
+		S3 x (S4)
+	`, matchers)
+
+	s.Prepare()
+
+	err := s.Parse([]string{"a", "-n", "abc", "b"})
+
+	valueErr, ok := err.(*fsm.ValueError)
+	require.True(t, ok, "expected a value error, got %#v", err)
+	require.Equal(t, intCon, valueErr.Container)
+	require.Equal(t, "abc", valueErr.Value)
+	require.Equal(t, 2, valueErr.Pos)
+	require.Error(t, valueErr.Err)
+}
+
+func TestParseErrorExpected(t *testing.T) {
+	var (
+		force     = &container.Container{Name: "f force", Names: []string{"-f", "--force"}, Value: values.NewBool(new(bool), false)}
+		recursive = &container.Container{Name: "r", Names: []string{"-r"}, Value: values.NewBool(new(bool), false)}
+		src       = &container.Container{Name: "SRC", Value: values.NewString(new(string), "")}
+		dst       = &container.Container{Name: "DST", Value: values.NewString(new(string), "")}
+		idx       = map[string]*container.Container{"-f": force, "--force": force, "-r": recursive}
+	)
+
+	// [-f] [-r] SRC [DST]
+	s1, s2, s3, s4, s5 := fsm.NewState(), fsm.NewState(), fsm.NewState(), fsm.NewState(), fsm.NewState()
+	s1.T(matcher.NewOpt(force, idx), s2)
+	s1.T(matcher.NewShortcut(), s2)
+	s2.T(matcher.NewOpt(recursive, idx), s3)
+	s2.T(matcher.NewShortcut(), s3)
+	s3.T(matcher.NewArg(src), s4)
+	s4.Terminal = true
+	s4.T(matcher.NewArg(dst), s5)
+	s5.Terminal = true
+	s1.Prepare()
+
+	cases := []struct {
+		args     []string
+		expected *fsm.ParseError
+	}{
+		{[]string{"-f"}, &fsm.ParseError{Pos: 1, Expected: []string{"-r", "SRC"}}},
+		{[]string{"-r", "-f"}, &fsm.ParseError{Pos: 2, Expected: []string{"SRC"}}},
+		{[]string{"a", "b", "c"}, &fsm.ParseError{Pos: 2, Terminal: true}},
+		{[]string{"a", "-x"}, &fsm.ParseError{Pos: 1, Expected: []string{"DST"}, Terminal: true}},
+	}
+
+	for _, cas := range cases {
+		err := s1.Parse(cas.args)
+		require.Equal(t, cas.expected, err, "args %q", cas.args)
+	}
+}
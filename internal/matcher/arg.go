@@ -22,10 +22,24 @@ func (arg *arg) Match(args []string, c *ParseContext) (bool, []string) {
 	if !c.RejectOptions && strings.HasPrefix(args[0], "-") && args[0] != "-" {
 		return false, args
 	}
-	c.Args[arg.arg] = append(c.Args[arg.arg], args[0])
+	c.AddArg(arg.arg, args[0], 0)
 	return true, args[1:]
 }
 
+func (arg *arg) Complete(prefix string, c *ParseContext) []string {
+	if arg.arg.Complete == nil {
+		return nil
+	}
+	var res []string
+	for _, v := range arg.arg.Complete(prefix) {
+		if !c.RejectOptions && strings.HasPrefix(v, "-") && v != "-" {
+			continue
+		}
+		res = append(res, v)
+	}
+	return res
+}
+
 func (*arg) Priority() int {
 	return 8
 }
@@ -33,3 +47,7 @@ func (*arg) Priority() int {
 func (arg *arg) String() string {
 	return arg.arg.Name
 }
+
+func (arg *arg) Describe() string {
+	return arg.arg.Name
+}
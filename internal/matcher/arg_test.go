@@ -12,6 +12,7 @@ func TestArgMatcher(t *testing.T) {
 	argMatcher := arg{arg: a}
 
 	require.Equal(t, "X", argMatcher.String())
+	require.Equal(t, "X", argMatcher.Describe())
 
 	{
 		pc := NewParseContext()
@@ -41,3 +42,27 @@ func TestArgMatcher(t *testing.T) {
 		require.True(t, ok, "arg should match options when the reject flag is set")
 	}
 }
+
+func TestArgMatcherComplete(t *testing.T) {
+	{
+		argMatcher := arg{arg: &container.Container{Name: "X"}}
+		pc := NewParseContext()
+		require.Nil(t, argMatcher.Complete("", &pc), "arg without a complete func should not propose anything")
+	}
+
+	argMatcher := arg{arg: &container.Container{
+		Name: "X",
+		Complete: func(prefix string) []string {
+			return []string{prefix + "a", "-", "-v"}
+		},
+	}}
+	{
+		pc := NewParseContext()
+		require.Equal(t, []string{"pa", "-"}, argMatcher.Complete("p", &pc), "arg should not propose options")
+	}
+	{
+		pc := NewParseContext()
+		pc.RejectOptions = true
+		require.Equal(t, []string{"pa", "-", "-v"}, argMatcher.Complete("p", &pc), "arg should propose options when the reject flag is set")
+	}
+}
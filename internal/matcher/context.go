@@ -8,6 +8,12 @@ type ParseContext struct {
 	Opts          map[*container.Container][]string
 	ExcludedOpts  map[*container.Container]struct{}
 	RejectOptions bool
+	// Positions holds the index in the parsed args of each one of the Args and Opts values
+	Positions map[*container.Container][]int
+	// ArgPos holds the index in the parsed args of each one of the args given to the matcher, if known
+	ArgPos []int
+	// AllowAbbreviations lets the long options be abbreviated to any unique prefix, e.g. --verb for --verbose
+	AllowAbbreviations bool
 }
 
 // NewParseContext create a new ParseContext
@@ -17,6 +23,7 @@ func NewParseContext() ParseContext {
 		Opts:          map[*container.Container][]string{},
 		ExcludedOpts:  map[*container.Container]struct{}{},
 		RejectOptions: false,
+		Positions:     map[*container.Container][]int{},
 	}
 }
 
@@ -29,4 +36,26 @@ func (pc ParseContext) Merge(o ParseContext) {
 	for k, vs := range o.Opts {
 		pc.Opts[k] = append(pc.Opts[k], vs...)
 	}
+
+	for k, ps := range o.Positions {
+		pc.Positions[k] = append(pc.Positions[k], ps...)
+	}
+}
+
+// AddOpt records value for opt, read from the arg at idx in the args given to the matcher
+func (pc *ParseContext) AddOpt(opt *container.Container, value string, idx int) {
+	pc.Opts[opt] = append(pc.Opts[opt], value)
+	pc.addPos(opt, idx)
+}
+
+// AddArg records value for arg, read from the arg at idx in the args given to the matcher
+func (pc *ParseContext) AddArg(arg *container.Container, value string, idx int) {
+	pc.Args[arg] = append(pc.Args[arg], value)
+	pc.addPos(arg, idx)
+}
+
+func (pc *ParseContext) addPos(con *container.Container, idx int) {
+	if idx < len(pc.ArgPos) {
+		pc.Positions[con] = append(pc.Positions[con], pc.ArgPos[idx])
+	}
 }
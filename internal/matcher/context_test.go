@@ -20,6 +20,9 @@ func TestNew(t *testing.T) {
 	require.Empty(t, c.ExcludedOpts)
 
 	require.False(t, c.RejectOptions)
+
+	require.NotNil(t, c.Positions)
+	require.Empty(t, c.Positions)
 }
 
 func TestMerge(t *testing.T) {
@@ -43,6 +46,10 @@ func TestMerge(t *testing.T) {
 	c2.Opts[o1] = []string{"o1.2"}
 	c2.Opts[o2] = []string{"o2"}
 
+	c1.Positions[a1] = []int{0}
+	c2.Positions[a1] = []int{3}
+	c2.Positions[o2] = []int{1}
+
 	c1.Merge(c2)
 
 	require.Equal(t, map[*container.Container][]string{
@@ -56,4 +63,9 @@ func TestMerge(t *testing.T) {
 		o2: {"o2"},
 		o3: {"o3"},
 	}, c1.Opts)
+
+	require.Equal(t, map[*container.Container][]int{
+		a1: {0, 3},
+		o2: {1},
+	}, c1.Positions)
 }
@@ -13,6 +13,22 @@ type Matcher interface {
 	Priority() int
 }
 
+/*
+Completer is implemented by the matchers which can propose candidates for the next arg, e.g. for shell completion
+*/
+type Completer interface {
+	// Complete returns the candidates for an arg starting with prefix
+	Complete(prefix string, c *ParseContext) []string
+}
+
+/*
+Describer is implemented by the matchers which can describe what they expect, e.g. for usage error messages
+*/
+type Describer interface {
+	// Describe returns a human readable name of what the matcher expects, e.g. SRC or --recursive
+	Describe() string
+}
+
 // IsShortcut is a helper to determine whether a given matcher is a Shortcut (always matches)
 func IsShortcut(matcher Matcher) bool {
 	_, ok := matcher.(shortcut)
@@ -28,9 +28,18 @@ func (o *opt) String() string {
 	return o.theOne.Names[0]
 }
 
+func (o *opt) Describe() string {
+	for _, name := range o.theOne.Names {
+		if strings.HasPrefix(name, "--") {
+			return name
+		}
+	}
+	return o.theOne.Names[0]
+}
+
 func (o *opt) Match(args []string, c *ParseContext) (bool, []string) {
 	if len(args) == 0 || c.RejectOptions {
-		return o.theOne.ValueSetFromEnv, args
+		return o.theOne.ValueSetExternally(), args
 	}
 
 	idx := 0
@@ -40,7 +49,7 @@ func (o *opt) Match(args []string, c *ParseContext) (bool, []string) {
 		case arg == "-":
 			idx++
 		case arg == "--":
-			return o.theOne.ValueSetFromEnv, args
+			return o.theOne.ValueSetExternally(), args
 		case strings.HasPrefix(arg, "--"):
 			matched, consumed, nargs := o.matchLongOpt(args, idx, c)
 
@@ -48,7 +57,7 @@ func (o *opt) Match(args []string, c *ParseContext) (bool, []string) {
 				return true, nargs
 			}
 			if consumed == 0 {
-				return o.theOne.ValueSetFromEnv, args
+				return o.theOne.ValueSetExternally(), args
 			}
 			idx += consumed
 
@@ -58,23 +67,22 @@ func (o *opt) Match(args []string, c *ParseContext) (bool, []string) {
 				return true, nargs
 			}
 			if consumed == 0 {
-				return o.theOne.ValueSetFromEnv, args
+				return o.theOne.ValueSetExternally(), args
 			}
 			idx += consumed
 
 		default:
-			return o.theOne.ValueSetFromEnv, args
+			return o.theOne.ValueSetExternally(), args
 		}
 	}
-	return o.theOne.ValueSetFromEnv, args
+	return o.theOne.ValueSetExternally(), args
 }
 
 func (o *opt) matchLongOpt(args []string, idx int, c *ParseContext) (bool, int, []string) {
 	arg := args[idx]
 	kv := strings.SplitN(arg, "=", 2)
-	name := kv[0]
-	opt, found := o.index[name]
-	if !found {
+	name, opt := LookupLongOpt(o.index, kv[0], c.AllowAbbreviations)
+	if opt == nil {
 		return false, 0, args
 	}
 
@@ -83,17 +91,31 @@ func (o *opt) matchLongOpt(args []string, idx int, c *ParseContext) (bool, int,
 		if opt != o.theOne {
 			return false, 1, args
 		}
+		if isNegatedName(opt, name) {
+			// --no-color=value makes no sense
+			return false, 0, args
+		}
 		value := kv[1]
 		if value == "" {
 			return false, 0, args
 		}
-		c.Opts[o.theOne] = append(c.Opts[o.theOne], value)
+		c.AddOpt(o.theOne, value, idx)
 		return true, 1, removeStringAt(idx, args)
 	case values.IsBool(opt.Value):
 		if opt != o.theOne {
 			return false, 1, args
 		}
-		c.Opts[o.theOne] = append(c.Opts[o.theOne], "true")
+		value := "true"
+		if isNegatedName(opt, name) {
+			value = "false"
+		}
+		c.AddOpt(o.theOne, value, idx)
+		return true, 1, removeStringAt(idx, args)
+	case opt.ValueOptional():
+		if opt != o.theOne {
+			return false, 1, args
+		}
+		c.AddOpt(o.theOne, opt.NoOptDefVal, idx)
 		return true, 1, removeStringAt(idx, args)
 	default:
 		if len(args[idx:]) < 2 {
@@ -106,7 +128,7 @@ func (o *opt) matchLongOpt(args []string, idx int, c *ParseContext) (bool, int,
 		if strings.HasPrefix(value, "-") {
 			return false, 0, args
 		}
-		c.Opts[o.theOne] = append(c.Opts[o.theOne], value)
+		c.AddOpt(o.theOne, value, idx+1)
 		return true, 2, removeStringsBetween(idx, idx+1, args)
 	}
 }
@@ -128,7 +150,7 @@ func (o *opt) matchShortOpt(args []string, idx int, c *ParseContext) (bool, int,
 		if value == "" {
 			return false, 0, args
 		}
-		c.Opts[o.theOne] = append(c.Opts[o.theOne], value)
+		c.AddOpt(o.theOne, value, idx)
 		return true, 1, removeStringAt(idx, args)
 
 	}
@@ -149,8 +171,11 @@ func (o *opt) matchShortOpt(args []string, idx int, c *ParseContext) (bool, int,
 				remIdx++
 				continue
 			}
+			if values.IsCounter(opt.Value) {
+				return o.matchFoldedCounter(args, idx, c)
+			}
 
-			c.Opts[o.theOne] = append(c.Opts[o.theOne], "true")
+			c.AddOpt(o.theOne, "true", idx)
 			newRem := rem[:remIdx] + rem[remIdx+1:]
 			if newRem == "" {
 				return true, 1, removeStringAt(idx, args)
@@ -159,6 +184,13 @@ func (o *opt) matchShortOpt(args []string, idx int, c *ParseContext) (bool, int,
 		}
 
 		value := rem[remIdx+1:]
+		if value == "" && opt.ValueOptional() {
+			// the value, if any, must be attached to the option, e.g. -calways
+			if opt != o.theOne {
+				return false, 1, args
+			}
+			value = opt.NoOptDefVal
+		}
 		if value == "" {
 			if len(args[idx+1:]) == 0 {
 				return false, 0, args
@@ -171,7 +203,7 @@ func (o *opt) matchShortOpt(args []string, idx int, c *ParseContext) (bool, int,
 			if strings.HasPrefix(value, "-") {
 				return false, 0, args
 			}
-			c.Opts[o.theOne] = append(c.Opts[o.theOne], value)
+			c.AddOpt(o.theOne, value, idx+1)
 
 			newRem := rem[:remIdx]
 			if newRem == "" {
@@ -186,7 +218,7 @@ func (o *opt) matchShortOpt(args []string, idx int, c *ParseContext) (bool, int,
 		if opt != o.theOne {
 			return false, 1, args
 		}
-		c.Opts[o.theOne] = append(c.Opts[o.theOne], value)
+		c.AddOpt(o.theOne, value, idx)
 		newRem := rem[:remIdx]
 		if newRem == "" {
 			return true, 1, removeStringAt(idx, args)
@@ -197,3 +229,66 @@ func (o *opt) matchShortOpt(args []string, idx int, c *ParseContext) (bool, int,
 
 	return false, 1, args
 }
+
+// matchFoldedCounter consumes all the occurrences of the counter option in the folded short options at idx, e.g. -vxv
+func (o *opt) matchFoldedCounter(args []string, idx int, c *ParseContext) (bool, int, []string) {
+	rem := args[idx][1:]
+	newRem := ""
+	for i := 0; i < len(rem); i++ {
+		opt := o.index["-"+rem[i:i+1]]
+		if opt == o.theOne {
+			c.AddOpt(o.theOne, "true", idx)
+			continue
+		}
+		if opt == nil || !values.IsBool(opt.Value) {
+			// the rest is the value of this option
+			newRem += rem[i:]
+			break
+		}
+		newRem += rem[i : i+1]
+	}
+
+	if newRem == "" {
+		return true, 1, removeStringAt(idx, args)
+	}
+	return true, 0, replaceStringAt(idx, "-"+newRem, args)
+}
+
+func isNegatedName(opt *container.Container, name string) bool {
+	for _, n := range opt.NegatedNames() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LookupLongOpt returns the option named name and its full name,
+// or if abbreviate is set, the only option having a long name starting with name
+func LookupLongOpt(index map[string]*container.Container, name string, abbreviate bool) (string, *container.Container) {
+	if opt, found := index[name]; found {
+		return name, opt
+	}
+	if !abbreviate {
+		return name, nil
+	}
+
+	var (
+		fullName string
+		res      *container.Container
+	)
+	for n, opt := range index {
+		if !strings.HasPrefix(n, "--") || !strings.HasPrefix(n, name) {
+			continue
+		}
+		if res != nil && res != opt {
+			// ambiguous
+			return name, nil
+		}
+		if res == nil || n < fullName {
+			fullName = n
+		}
+		res = opt
+	}
+	return fullName, res
+}
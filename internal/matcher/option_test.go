@@ -25,6 +25,7 @@ func TestBoolOptMatcher(t *testing.T) {
 	}
 
 	require.Equal(t, "-f", optMatcher.String())
+	require.Equal(t, "--force", optMatcher.Describe())
 
 	cases := []struct {
 		args  []string
@@ -59,6 +60,145 @@ func TestBoolOptMatcher(t *testing.T) {
 	}
 }
 
+func TestCounterOptMatcher(t *testing.T) {
+	verboseOpt := &container.Container{Names: []string{"-v", "--verbose"}, Value: values.NewCounter(new(int), 0)}
+
+	optMatcher := opt{
+		theOne: verboseOpt,
+		index: map[string]*container.Container{
+			"-v":        verboseOpt,
+			"--verbose": verboseOpt,
+			"-g":        {Names: []string{"-g"}, Value: values.NewBool(new(bool), false)},
+			"-n":        {Names: []string{"-n"}, Value: values.NewString(new(string), "")},
+		},
+	}
+
+	cases := []struct {
+		args  []string
+		nargs []string
+		val   []string
+	}{
+		{[]string{"-v", "x"}, []string{"x"}, []string{"true"}},
+		{[]string{"--verbose", "x"}, []string{"x"}, []string{"true"}},
+		{[]string{"--verbose=2", "x"}, []string{"x"}, []string{"2"}},
+		{[]string{"-vvv", "x"}, []string{"x"}, []string{"true", "true", "true"}},
+		{[]string{"-vgv", "x"}, []string{"-g", "x"}, []string{"true", "true"}},
+		{[]string{"-gvv", "x"}, []string{"-g", "x"}, []string{"true", "true"}},
+		{[]string{"-vnvv", "x"}, []string{"-nvv", "x"}, []string{"true"}},
+	}
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("%#v", cas), func(t *testing.T) {
+			pc := NewParseContext()
+			ok, nargs := optMatcher.Match(cas.args, &pc)
+			require.True(t, ok, "opt should match")
+			require.Equal(t, cas.nargs, nargs, "opt should consume all the occurrences in the folded options")
+			require.Equal(t, cas.val, pc.Opts[verboseOpt])
+		})
+	}
+}
+
+func TestNegatableOptMatcher(t *testing.T) {
+	colorOpt := &container.Container{Names: []string{"-c", "--color"}, Value: values.NewBool(new(bool), true), Negatable: true}
+
+	optMatcher := opt{
+		theOne: colorOpt,
+		index: map[string]*container.Container{
+			"-c":         colorOpt,
+			"--color":    colorOpt,
+			"--no-color": colorOpt,
+		},
+	}
+
+	cases := []struct {
+		args  []string
+		ok    bool
+		nargs []string
+		val   []string
+	}{
+		{[]string{"--color", "x"}, true, []string{"x"}, []string{"true"}},
+		{[]string{"--no-color", "x"}, true, []string{"x"}, []string{"false"}},
+		{[]string{"--color=false", "x"}, true, []string{"x"}, []string{"false"}},
+		{[]string{"--no-color=true", "x"}, false, []string{"--no-color=true", "x"}, nil},
+	}
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("%#v", cas), func(t *testing.T) {
+			pc := NewParseContext()
+			ok, nargs := optMatcher.Match(cas.args, &pc)
+			require.Equal(t, cas.ok, ok)
+			require.Equal(t, cas.nargs, nargs)
+			require.Equal(t, cas.val, pc.Opts[colorOpt])
+		})
+	}
+}
+
+func TestOptionalValueOptMatcher(t *testing.T) {
+	colorOpt := &container.Container{Names: []string{"-c", "--color"}, Value: values.NewString(new(string), ""), NoOptDefVal: "always"}
+
+	optMatcher := opt{
+		theOne: colorOpt,
+		index: map[string]*container.Container{
+			"-c":      colorOpt,
+			"--color": colorOpt,
+			"-g":      {Names: []string{"-g"}, Value: values.NewBool(new(bool), false)},
+		},
+	}
+
+	cases := []struct {
+		args  []string
+		nargs []string
+		val   []string
+	}{
+		{[]string{"--color", "never"}, []string{"never"}, []string{"always"}},
+		{[]string{"--color=never", "x"}, []string{"x"}, []string{"never"}},
+		{[]string{"-c", "never"}, []string{"never"}, []string{"always"}},
+		{[]string{"-cnever", "x"}, []string{"x"}, []string{"never"}},
+		{[]string{"-c=never", "x"}, []string{"x"}, []string{"never"}},
+		{[]string{"-gc", "x"}, []string{"-g", "x"}, []string{"always"}},
+	}
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("%#v", cas), func(t *testing.T) {
+			pc := NewParseContext()
+			ok, nargs := optMatcher.Match(cas.args, &pc)
+			require.True(t, ok, "opt should match")
+			require.Equal(t, cas.nargs, nargs)
+			require.Equal(t, cas.val, pc.Opts[colorOpt])
+		})
+	}
+}
+
+func TestAbbreviatedOptMatcher(t *testing.T) {
+	verboseOpt := &container.Container{Names: []string{"--verbose"}, Value: values.NewBool(new(bool), false)}
+	nameOpt := &container.Container{Names: []string{"--name"}, Value: values.NewString(new(string), "")}
+	index := map[string]*container.Container{
+		"--verbose": verboseOpt,
+		"--version": {Names: []string{"--version"}, Value: values.NewBool(new(bool), false)},
+		"--name":    nameOpt,
+	}
+
+	cases := []struct {
+		theOne *container.Container
+		abbrev bool
+		args   []string
+		ok     bool
+		val    []string
+	}{
+		{verboseOpt, true, []string{"--verb"}, true, []string{"true"}},
+		{verboseOpt, false, []string{"--verb"}, false, nil},
+		{verboseOpt, true, []string{"--ver"}, false, nil},
+		{nameOpt, true, []string{"--n", "x"}, true, []string{"x"}},
+		{nameOpt, true, []string{"--na=x"}, true, []string{"x"}},
+	}
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("%v %v", cas.abbrev, cas.args), func(t *testing.T) {
+			pc := NewParseContext()
+			pc.AllowAbbreviations = cas.abbrev
+			ok, _ := (&opt{theOne: cas.theOne, index: index}).Match(cas.args, &pc)
+			require.Equal(t, cas.ok, ok)
+			require.Equal(t, cas.val, pc.Opts[cas.theOne])
+		})
+	}
+}
+
 func TestOptMatcher(t *testing.T) {
 	names := []string{"-f", "--force"}
 	opts := []*container.Container{
@@ -165,3 +305,25 @@ func TestOptNegatives(t *testing.T) {
 		}
 	}
 }
+
+func TestOptMatcherValueSetExternally(t *testing.T) {
+	opts := []*container.Container{
+		{Names: []string{"-f"}, Value: values.NewString(new(string), ""), ValueSetFromEnv: true},
+		{Names: []string{"-f"}, Value: values.NewString(new(string), ""), ValueSetFromConfig: true},
+	}
+
+	for _, forceOpt := range opts {
+		optMatcher := opt{
+			theOne: forceOpt,
+			index: map[string]*container.Container{
+				"-f": forceOpt,
+			},
+		}
+
+		pc := NewParseContext()
+		ok, nargs := optMatcher.Match([]string{"x"}, &pc)
+		require.True(t, ok, "an option set from env or config should match without being in the args")
+		require.Equal(t, []string{"x"}, nargs)
+		require.Nil(t, pc.Opts[forceOpt])
+	}
+}
@@ -29,11 +29,13 @@ func (om *options) Match(args []string, c *ParseContext) (bool, []string) {
 		return false, args
 	}
 
+	c.ArgPos = RemainingPositions(args, c.ArgPos, nargs)
 	for {
 		ok, nnargs := om.try(nargs, c)
 		if !ok {
 			return true, nargs
 		}
+		c.ArgPos = RemainingPositions(nargs, c.ArgPos, nnargs)
 		nargs = nnargs
 	}
 }
@@ -47,7 +49,7 @@ func (om *options) try(args []string, c *ParseContext) (bool, []string) {
 			continue
 		}
 		if ok, nargs := (&opt{theOne: o, index: om.index}).Match(args, c); ok {
-			if o.ValueSetFromEnv {
+			if o.ValueSetExternally() {
 				c.ExcludedOpts[o] = struct{}{}
 			}
 			return true, nargs
@@ -64,3 +66,7 @@ func (om *options) String() string {
 	}
 	return names
 }
+
+func (om *options) Describe() string {
+	return "OPTIONS"
+}
@@ -27,6 +27,7 @@ func TestOptsMatcher(t *testing.T) {
 	}
 
 	require.Equal(t, "-fg", opts.String())
+	require.Equal(t, "OPTIONS", opts.Describe())
 
 	cases := []struct {
 		args  []string
@@ -104,3 +105,40 @@ func TestOptsMatcherInfiniteLoop(t *testing.T) {
 	}
 
 }
+
+func TestOptsMatcherPositions(t *testing.T) {
+	force := &container.Container{Names: []string{"-f", "--force"}, Value: values.NewBool(new(bool), false)}
+	green := &container.Container{Names: []string{"-g", "--green"}, Value: values.NewString(new(string), "")}
+	opts := options{
+		options: []*container.Container{force, green},
+		index:   map[string]*container.Container{},
+	}
+	for _, o := range opts.options {
+		for _, n := range o.Names {
+			opts.index[n] = o
+		}
+	}
+
+	cases := []struct {
+		args     []string
+		expected map[*container.Container][]int
+	}{
+		{[]string{"-g", "x", "-f", "y"}, map[*container.Container][]int{force: {12}, green: {11}}},
+		{[]string{"-f", "--green=x", "y"}, map[*container.Container][]int{force: {10}, green: {11}}},
+		{[]string{"-fg", "x", "y"}, map[*container.Container][]int{force: {10}, green: {11}}},
+		{[]string{"-gx", "-f", "-g", "y", "z"}, map[*container.Container][]int{force: {11}, green: {10, 13}}},
+	}
+
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("args %#v ", cas.args), func(t *testing.T) {
+			pc := NewParseContext()
+			for i := range cas.args {
+				pc.ArgPos = append(pc.ArgPos, 10+i)
+			}
+
+			ok, _ := opts.Match(cas.args, &pc)
+			require.True(t, ok)
+			require.Equal(t, cas.expected, pc.Positions)
+		})
+	}
+}
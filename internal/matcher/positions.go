@@ -0,0 +1,39 @@
+package matcher
+
+import "strings"
+
+/*
+RemainingPositions returns the positions of the remaining args rem returned by a matcher which was given args at positions pos,
+or nil if pos is.
+A matcher can only remove args, or remove some of the short options folded in an arg,
+which makes it possible to align rem with args starting from the end.
+*/
+func RemainingPositions(args []string, pos []int, rem []string) []int {
+	if pos == nil {
+		return nil
+	}
+	remPos := make([]int, len(rem))
+	i := len(args) - 1
+	for j := len(rem) - 1; j >= 0; j-- {
+		for i >= 0 && !folded(args[i], rem[j]) {
+			i--
+		}
+		if i < 0 {
+			break
+		}
+		remPos[j] = pos[i]
+		i--
+	}
+	return remPos
+}
+
+// folded checks if rem is either arg or arg with some of its folded short options removed
+func folded(arg, rem string) bool {
+	if arg == rem {
+		return true
+	}
+	shortOpts := func(s string) bool {
+		return strings.HasPrefix(s, "-") && !strings.HasPrefix(s, "--")
+	}
+	return shortOpts(arg) && shortOpts(rem) && len(rem) < len(arg)
+}
@@ -2,7 +2,11 @@ package values
 
 import (
 	"flag"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -15,30 +19,153 @@ func IsBool(v flag.Value) bool {
 	return false
 }
 
-// SetFromEnv fills a value from a list of env vars
-func SetFromEnv(into flag.Value, envVars string) bool {
+// IsCounter checks if a given value counts the occurrences of an option, i.e. implements the CounterValued interface
+func IsCounter(v flag.Value) bool {
+	if cv, ok := v.(CounterValued); ok {
+		return cv.IsCounter()
+	}
+
+	return false
+}
+
+// SetFromEnv fills a value from a list of env vars, and returns the name of the env var used (or an empty string if none was).
+// The raw values are first passed to check if not nil, and the env vars with a rejected value are skipped
+func SetFromEnv(into flag.Value, envVars string, check func(string) error) string {
+	return setFromEnv(into, envVars, check, func(ev string) (string, string) {
+		return ev, os.Getenv(ev)
+	})
+}
+
+// SetFromSecretEnv is like SetFromEnv, but if an env var VAR is not set, its value is read from the file named by the VAR_FILE env var
+// with the trailing newlines stripped, in which case VAR_FILE is returned as the name of the env var used
+func SetFromSecretEnv(into flag.Value, envVars string, check func(string) error) string {
+	return setFromEnv(into, envVars, check, func(ev string) (string, string) {
+		if v := os.Getenv(ev); len(v) > 0 {
+			return ev, v
+		}
+		file := os.Getenv(ev + "_FILE")
+		if len(file) == 0 {
+			return ev, ""
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return ev, ""
+		}
+		return ev + "_FILE", strings.TrimRight(string(data), "\r\n")
+	})
+}
+
+func setFromEnv(into flag.Value, envVars string, check func(string) error, getenv func(string) (string, string)) string {
 	multiValued, isMulti := into.(MultiValued)
 
 	if len(envVars) > 0 {
 		for _, ev := range strings.Fields(envVars) {
-			v := os.Getenv(ev)
+			name, v := getenv(ev)
 			if len(v) == 0 {
 				continue
 			}
 			if !isMulti {
+				if !accept(check, v) {
+					continue
+				}
 				if err := into.Set(v); err == nil {
-					return true
+					return name
 				}
 				continue
 			}
 
 			vs := strings.Split(v, ",")
+			if !accept(check, vs...) {
+				continue
+			}
 			if err := setMultivalued(multiValued, vs); err == nil {
-				return true
+				return name
 			}
 		}
 	}
-	return false
+	return ""
+}
+
+// SetFromConfig fills a value from a decoded config file value: a scalar, a list of scalars for multi valued values,
+// or a map of scalars for key=value multi valued values.
+// The raw values are first passed to check if not nil, and the config value is skipped if one of them is rejected
+func SetFromConfig(into flag.Value, v interface{}, check func(string) error) bool {
+	multiValued, isMulti := into.(MultiValued)
+	if !isMulti {
+		s, ok := configString(v)
+		if !ok || !accept(check, s) {
+			return false
+		}
+		return into.Set(s) == nil
+	}
+
+	vs, ok := configStrings(v)
+	if !ok || len(vs) == 0 || !accept(check, vs...) {
+		return false
+	}
+	return setMultivalued(multiValued, vs) == nil
+}
+
+func configStrings(v interface{}) ([]string, bool) {
+	switch x := v.(type) {
+	case []interface{}:
+		res := make([]string, 0, len(x))
+		for _, e := range x {
+			s, ok := configString(e)
+			if !ok {
+				return nil, false
+			}
+			res = append(res, s)
+		}
+		return res, true
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		res := make([]string, 0, len(x))
+		for _, k := range keys {
+			s, ok := configString(x[k])
+			if !ok {
+				return nil, false
+			}
+			res = append(res, k+"="+s)
+		}
+		return res, true
+	default:
+		s, ok := configString(v)
+		if !ok {
+			return nil, false
+		}
+		return []string{s}, true
+	}
+}
+
+func configString(v interface{}) (string, bool) {
+	switch x := v.(type) {
+	case nil, []interface{}, map[string]interface{}:
+		return "", false
+	case string:
+		return x, true
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	default:
+		return fmt.Sprint(x), true
+	}
+}
+
+func accept(check func(string) error, vs ...string) bool {
+	if check == nil {
+		return true
+	}
+	for _, v := range vs {
+		if err := check(strings.TrimSpace(v)); err != nil {
+			return false
+		}
+	}
+	return true
 }
 
 func setMultivalued(into MultiValued, values []string) error {
@@ -1,6 +1,9 @@
 package values
 
 import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -24,6 +27,7 @@ func TestSetFromEnv(t *testing.T) {
 	cases := []struct {
 		desc     string
 		envVars  string
+		check    func(string) error
 		setup    func() (flag.Value, interface{})
 		expected bool
 		val      interface{}
@@ -191,6 +195,43 @@ func TestSetFromEnv(t *testing.T) {
 			expected: true,
 			val:      []int{7, 8, 9},
 		},
+		{
+			desc:    "Rejected env var",
+			envVars: "A B",
+			check:   onlyDodo,
+			setup: func() (flag.Value, interface{}) {
+				os.Setenv("A", "Mr")
+				os.Setenv("B", "DoDo")
+				var s string
+				return NewString(&s, "default"), &s
+			},
+			expected: true,
+			val:      "DoDo",
+		},
+		{
+			desc:    "Rejected env var values",
+			envVars: "A",
+			check:   onlyDodo,
+			setup: func() (flag.Value, interface{}) {
+				os.Setenv("A", "DoDo, Mr")
+				var s []string
+				return NewStrings(&s, []string{"default"}), &s
+			},
+			expected: false,
+			val:      []string{"default"},
+		},
+		{
+			desc:    "Accepted env var values",
+			envVars: "A",
+			check:   onlyDodo,
+			setup: func() (flag.Value, interface{}) {
+				os.Setenv("A", "DoDo, DoDo")
+				var s []string
+				return NewStrings(&s, nil), &s
+			},
+			expected: true,
+			val:      []string{"DoDo", "DoDo"},
+		},
 	}
 
 	for _, cas := range cases {
@@ -199,7 +240,7 @@ func TestSetFromEnv(t *testing.T) {
 
 			val, into := cas.setup()
 
-			actual := SetFromEnv(val, cas.envVars)
+			actual := SetFromEnv(val, cas.envVars, cas.check) != ""
 
 			require.Equal(t, cas.expected, actual)
 
@@ -213,3 +254,86 @@ func TestSetFromEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestSetFromEnvReturnsTheEnvVarName(t *testing.T) {
+	os.Setenv("A", "")
+	os.Setenv("B", "Mr")
+	os.Setenv("C", "DoDo")
+
+	var s string
+	require.Equal(t, "C", SetFromEnv(NewString(&s, ""), "A B C", onlyDodo))
+	require.Equal(t, "", SetFromEnv(NewString(&s, ""), "A B", onlyDodo))
+}
+
+func onlyDodo(v string) error {
+	if v != "DoDo" {
+		return errors.New("not a dodo")
+	}
+	return nil
+}
+
+func TestSetFromConfig(t *testing.T) {
+	var (
+		s  string
+		i  int
+		b  bool
+		ss []string
+		m  map[string]string
+	)
+
+	require.True(t, SetFromConfig(NewString(&s, ""), "a", nil))
+	require.Equal(t, "a", s)
+
+	require.True(t, SetFromConfig(NewInt(&i, 0), float64(42), nil))
+	require.Equal(t, 42, i)
+
+	require.True(t, SetFromConfig(NewBool(&b, false), true, nil))
+	require.True(t, b)
+
+	require.True(t, SetFromConfig(NewStrings(&ss, nil), []interface{}{"a", float64(1.5)}, nil))
+	require.Equal(t, []string{"a", "1.5"}, ss)
+
+	require.True(t, SetFromConfig(NewStrings(&ss, nil), "single", nil))
+	require.Equal(t, []string{"single"}, ss)
+
+	require.True(t, SetFromConfig(NewStringMap(&m, nil), map[string]interface{}{"b": "2", "a": float64(1)}, nil))
+	require.Equal(t, map[string]string{"a": "1", "b": "2"}, m)
+
+	s = ""
+	require.False(t, SetFromConfig(NewString(&s, "default"), []interface{}{"a", "b"}, nil))
+	require.False(t, SetFromConfig(NewString(&s, "default"), nil, nil))
+	require.False(t, SetFromConfig(NewString(&s, "default"), map[string]interface{}{"a": "b"}, nil))
+	require.False(t, SetFromConfig(NewString(&s, "default"), "Mr", onlyDodo))
+	require.False(t, SetFromConfig(NewInt(&i, 7), "x", nil))
+	require.False(t, SetFromConfig(NewStrings(&ss, nil), []interface{}{[]interface{}{"nested"}}, nil))
+	require.Equal(t, "default", s)
+	require.Equal(t, 7, i)
+}
+
+func TestSetFromSecretEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "token")
+	require.NoError(t, ioutil.WriteFile(file, []byte("s3cr3t\r\n\n"), 0600))
+
+	os.Setenv("TOKEN", "")
+	os.Setenv("TOKEN_FILE", file)
+	os.Setenv("OTHER", "")
+	os.Setenv("OTHER_FILE", filepath.Join(dir, "missing"))
+	defer os.Unsetenv("TOKEN_FILE")
+	defer os.Unsetenv("OTHER_FILE")
+
+	var s string
+	require.Equal(t, "TOKEN_FILE", SetFromSecretEnv(NewString(&s, ""), "OTHER TOKEN", nil))
+	require.Equal(t, "s3cr3t", s)
+
+	os.Setenv("TOKEN", "from-env")
+	require.Equal(t, "TOKEN", SetFromSecretEnv(NewString(&s, ""), "TOKEN", nil))
+	require.Equal(t, "from-env", s)
+	os.Setenv("TOKEN", "")
+
+	require.Equal(t, "", SetFromEnv(NewString(&s, "default"), "TOKEN", nil), "SetFromEnv should ignore the _FILE env vars")
+	require.Equal(t, "default", s)
+}
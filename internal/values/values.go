@@ -3,7 +3,10 @@ package values
 import (
 	"flag"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // BoolValued is an interface values can implement to indicate that they are a bool option, i.e. can be set without providing a value with just -f for example
@@ -13,6 +16,14 @@ type BoolValued interface {
 	IsBoolFlag() bool
 }
 
+// CounterValued is an interface values can implement to indicate that they count the occurrences of a bool option,
+// i.e. all the occurrences in a folded -vvv are consumed at once
+type CounterValued interface {
+	BoolValued
+	// IsCounter should return true to indicate that this value counts the occurrences of the option
+	IsCounter() bool
+}
+
 // MultiValued is an interface ti indicate that a value can hold multiple values
 type MultiValued interface {
 	flag.Value
@@ -69,6 +80,71 @@ func (bo *BoolValue) IsDefault() bool {
 	return !bool(*bo)
 }
 
+/******************************************************************************/
+/* COUNTER                                                                    */
+/******************************************************************************/
+
+// CounterValue is a flag.Value type counting the occurrences of an option
+type CounterValue int
+
+var (
+	_ flag.Value    = NewCounter(new(int), 0)
+	_ CounterValued = NewCounter(new(int), 0)
+	_ MultiValued   = NewCounter(new(int), 0)
+	_ DefaultValued = NewCounter(new(int), 0)
+)
+
+// NewCounter creates a new counter value
+func NewCounter(into *int, v int) *CounterValue {
+	*into = v
+	return (*CounterValue)(into)
+}
+
+// Set increments the counter for a true bool value, resets it for a false one, or adds the provided count, e.g. 3
+func (co *CounterValue) Set(s string) error {
+	if b, err := strconv.ParseBool(s); err == nil {
+		if !b {
+			*co = 0
+			return nil
+		}
+		*co++
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return fmt.Errorf("negative count %d", n)
+	}
+	*co += CounterValue(n)
+	return nil
+}
+
+func (co *CounterValue) String() string {
+	return fmt.Sprintf("%v", *co)
+}
+
+// IsBoolFlag returns true
+func (co *CounterValue) IsBoolFlag() bool {
+	return true
+}
+
+// IsCounter returns true
+func (co *CounterValue) IsCounter() bool {
+	return true
+}
+
+// Clear resets the counter
+func (co *CounterValue) Clear() {
+	*co = 0
+}
+
+// IsDefault return true if the counter is zero
+func (co *CounterValue) IsDefault() bool {
+	return *co == 0
+}
+
 /******************************************************************************/
 /* STRING                                                                        */
 /******************************************************************************/
@@ -309,3 +385,328 @@ func (ia *Floats64Value) Clear() {
 func (ia *Floats64Value) IsDefault() bool {
 	return len(*ia) == 0
 }
+
+/******************************************************************************/
+/* DURATION                                                                   */
+/******************************************************************************/
+
+// DurationValue is a flag.Value type holding time.Duration values
+type DurationValue time.Duration
+
+var (
+	_ flag.Value    = NewDuration(new(time.Duration), 0)
+	_ DefaultValued = NewDuration(new(time.Duration), 0)
+)
+
+// NewDuration creates a new duration value
+func NewDuration(into *time.Duration, v time.Duration) *DurationValue {
+	*into = v
+	return (*DurationValue)(into)
+}
+
+// Set sets the value from a provided string, e.g. 1h30m
+func (da *DurationValue) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*da = DurationValue(d)
+	return nil
+}
+
+func (da *DurationValue) String() string {
+	return time.Duration(*da).String()
+}
+
+// IsDefault return true if the duration is zero
+func (da *DurationValue) IsDefault() bool {
+	return *da == 0
+}
+
+/******************************************************************************/
+/* DURATIONS                                                                  */
+/******************************************************************************/
+
+// DurationsValue is a flag.Value type holding time.Duration slices values
+type DurationsValue []time.Duration
+
+var (
+	_ flag.Value    = NewDurations(new([]time.Duration), nil)
+	_ MultiValued   = NewDurations(new([]time.Duration), nil)
+	_ DefaultValued = NewDurations(new([]time.Duration), nil)
+)
+
+// NewDurations creates a new multi-duration value
+func NewDurations(into *[]time.Duration, v []time.Duration) *DurationsValue {
+	*into = v
+	return (*DurationsValue)(into)
+}
+
+// Set sets the value from a provided string, e.g. 1h30m
+func (da *DurationsValue) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*da = append(*da, d)
+	return nil
+}
+
+func (da *DurationsValue) String() string {
+	res := "["
+	for idx, d := range *da {
+		if idx > 0 {
+			res += ", "
+		}
+		res += d.String()
+	}
+	return res + "]"
+}
+
+// Clear clears the slice
+func (da *DurationsValue) Clear() {
+	*da = nil
+}
+
+// IsDefault return true if the duration slice is empty
+func (da *DurationsValue) IsDefault() bool {
+	return len(*da) == 0
+}
+
+/******************************************************************************/
+/* TIME                                                                       */
+/******************************************************************************/
+
+// DefaultTimeLayout is the layout used to parse and format time values when none is specified
+const DefaultTimeLayout = time.RFC3339
+
+// TimeValue is a flag.Value type holding time.Time values parsed and formatted according to a layout
+type TimeValue struct {
+	into   *time.Time
+	layout string
+}
+
+var (
+	_ flag.Value    = NewTime(new(time.Time), time.Time{}, "")
+	_ DefaultValued = NewTime(new(time.Time), time.Time{}, "")
+)
+
+// NewTime creates a new time value using layout (DefaultTimeLayout if empty) to parse and format the time
+func NewTime(into *time.Time, v time.Time, layout string) *TimeValue {
+	*into = v
+	if layout == "" {
+		layout = DefaultTimeLayout
+	}
+	return &TimeValue{into: into, layout: layout}
+}
+
+// Set sets the value from a provided string formatted according to the layout
+func (ta *TimeValue) Set(s string) error {
+	t, err := time.Parse(ta.layout, s)
+	if err != nil {
+		return err
+	}
+	*ta.into = t
+	return nil
+}
+
+func (ta *TimeValue) String() string {
+	return ta.into.Format(ta.layout)
+}
+
+// IsDefault return true if the time is the zero time
+func (ta *TimeValue) IsDefault() bool {
+	return ta.into.IsZero()
+}
+
+/******************************************************************************/
+/* TIMES                                                                      */
+/******************************************************************************/
+
+// TimesValue is a flag.Value type holding time.Time slices values parsed and formatted according to a layout
+type TimesValue struct {
+	into   *[]time.Time
+	layout string
+}
+
+var (
+	_ flag.Value    = NewTimes(new([]time.Time), nil, "")
+	_ MultiValued   = NewTimes(new([]time.Time), nil, "")
+	_ DefaultValued = NewTimes(new([]time.Time), nil, "")
+)
+
+// NewTimes creates a new multi-time value using layout (DefaultTimeLayout if empty) to parse and format the times
+func NewTimes(into *[]time.Time, v []time.Time, layout string) *TimesValue {
+	*into = v
+	if layout == "" {
+		layout = DefaultTimeLayout
+	}
+	return &TimesValue{into: into, layout: layout}
+}
+
+// Set sets the value from a provided string formatted according to the layout
+func (ta *TimesValue) Set(s string) error {
+	t, err := time.Parse(ta.layout, s)
+	if err != nil {
+		return err
+	}
+	*ta.into = append(*ta.into, t)
+	return nil
+}
+
+func (ta *TimesValue) String() string {
+	res := "["
+	for idx, t := range *ta.into {
+		if idx > 0 {
+			res += ", "
+		}
+		res += t.Format(ta.layout)
+	}
+	return res + "]"
+}
+
+// Clear clears the slice
+func (ta *TimesValue) Clear() {
+	*ta.into = nil
+}
+
+// IsDefault return true if the time slice is empty
+func (ta *TimesValue) IsDefault() bool {
+	return len(*ta.into) == 0
+}
+
+/******************************************************************************/
+/* STRING MAP                                                                 */
+/******************************************************************************/
+
+// StringMapValue is a flag.Value type holding string maps values
+type StringMapValue map[string]string
+
+var (
+	_ flag.Value    = NewStringMap(new(map[string]string), nil)
+	_ MultiValued   = NewStringMap(new(map[string]string), nil)
+	_ DefaultValued = NewStringMap(new(map[string]string), nil)
+)
+
+// NewStringMap creates a new string map value
+func NewStringMap(into *map[string]string, v map[string]string) *StringMapValue {
+	*into = v
+	return (*StringMapValue)(into)
+}
+
+// Set adds a key=value pair to the map, failing if the key was already set
+func (sm *StringMapValue) Set(s string) error {
+	k, v, err := splitKeyValue(s)
+	if err != nil {
+		return err
+	}
+	if _, found := (*sm)[k]; found {
+		return fmt.Errorf("duplicate key %q", k)
+	}
+	if *sm == nil {
+		*sm = map[string]string{}
+	}
+	(*sm)[k] = v
+	return nil
+}
+
+func (sm *StringMapValue) String() string {
+	keys := make([]string, 0, len(*sm))
+	for k := range *sm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := "["
+	for idx, k := range keys {
+		if idx > 0 {
+			res += ", "
+		}
+		res += fmt.Sprintf("%s=%#v", k, (*sm)[k])
+	}
+	return res + "]"
+}
+
+// Clear clears the map
+func (sm *StringMapValue) Clear() {
+	*sm = nil
+}
+
+// IsDefault return true if the string map is empty
+func (sm *StringMapValue) IsDefault() bool {
+	return len(*sm) == 0
+}
+
+/******************************************************************************/
+/* INT MAP                                                                    */
+/******************************************************************************/
+
+// IntMapValue is a flag.Value type holding int maps values
+type IntMapValue map[string]int
+
+var (
+	_ flag.Value    = NewIntMap(new(map[string]int), nil)
+	_ MultiValued   = NewIntMap(new(map[string]int), nil)
+	_ DefaultValued = NewIntMap(new(map[string]int), nil)
+)
+
+// NewIntMap creates a new int map value
+func NewIntMap(into *map[string]int, v map[string]int) *IntMapValue {
+	*into = v
+	return (*IntMapValue)(into)
+}
+
+// Set adds a key=value pair to the map, failing if the value is not an int or if the key was already set
+func (im *IntMapValue) Set(s string) error {
+	k, v, err := splitKeyValue(s)
+	if err != nil {
+		return err
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+	if _, found := (*im)[k]; found {
+		return fmt.Errorf("duplicate key %q", k)
+	}
+	if *im == nil {
+		*im = map[string]int{}
+	}
+	(*im)[k] = i
+	return nil
+}
+
+func (im *IntMapValue) String() string {
+	keys := make([]string, 0, len(*im))
+	for k := range *im {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := "["
+	for idx, k := range keys {
+		if idx > 0 {
+			res += ", "
+		}
+		res += fmt.Sprintf("%s=%v", k, (*im)[k])
+	}
+	return res + "]"
+}
+
+// Clear clears the map
+func (im *IntMapValue) Clear() {
+	*im = nil
+}
+
+// IsDefault return true if the int map is empty
+func (im *IntMapValue) IsDefault() bool {
+	return len(*im) == 0
+}
+
+func splitKeyValue(s string) (string, string, error) {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return "", "", fmt.Errorf("expected a key=value pair, got %q", s)
+	}
+	return kv[0], kv[1], nil
+}
@@ -2,6 +2,7 @@ package values
 
 import (
 	"testing"
+	"time"
 
 	"flag"
 
@@ -320,3 +321,199 @@ func TestFloats64Param(t *testing.T) {
 		})
 	}
 }
+
+func TestDurationParam(t *testing.T) {
+	var into time.Duration
+
+	param := NewDuration(&into, 0)
+
+	cases := []struct {
+		input  string
+		err    bool
+		result time.Duration
+		string string
+	}{
+		{"1h30m", false, 90 * time.Minute, "1h30m0s"},
+		{"-5s", false, -5 * time.Second, "-5s"},
+		{"0", false, 0, "0s"},
+		{"5", true, 0, ""},
+		{"", true, 0, ""},
+	}
+
+	for _, cas := range cases {
+		t.Logf("testing .Set() with %q", cas.input)
+
+		err := param.Set(cas.input)
+
+		if cas.err {
+			require.Errorf(t, err, "value %q should have returned an error", cas.input)
+			continue
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, cas.result, into)
+		require.Equal(t, cas.string, param.String())
+	}
+
+	require.True(t, NewDuration(&into, 0).IsDefault())
+	require.False(t, NewDuration(&into, time.Second).IsDefault())
+}
+
+func TestDurationsParam(t *testing.T) {
+	var into []time.Duration
+	param := NewDurations(&into, nil)
+
+	require.NoError(t, param.Set("1s"))
+	require.NoError(t, param.Set("2m"))
+
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, into)
+	require.Equal(t, `[1s, 2m0s]`, param.String())
+
+	require.Error(t, param.Set("c"))
+	require.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, into)
+
+	require.False(t, param.IsDefault())
+
+	param.Clear()
+
+	require.Empty(t, into)
+	require.Equal(t, `[]`, param.String())
+	require.True(t, param.IsDefault())
+}
+
+func TestTimeParam(t *testing.T) {
+	var into time.Time
+
+	param := NewTime(&into, time.Time{}, "")
+	require.True(t, param.IsDefault())
+
+	require.NoError(t, param.Set("2020-01-02T03:04:05Z"))
+	require.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), into)
+	require.Equal(t, "2020-01-02T03:04:05Z", param.String())
+	require.False(t, param.IsDefault())
+
+	require.Error(t, param.Set("2020-01-02"))
+
+	param = NewTime(&into, time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC), "2006-01-02")
+	require.Equal(t, "2021-05-06", param.String())
+
+	require.NoError(t, param.Set("2020-01-02"))
+	require.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), into)
+
+	require.Error(t, param.Set("2020-01-02T03:04:05Z"))
+}
+
+func TestTimesParam(t *testing.T) {
+	var into []time.Time
+	param := NewTimes(&into, nil, "2006-01-02")
+
+	require.True(t, param.IsDefault())
+
+	require.NoError(t, param.Set("2020-01-02"))
+	require.NoError(t, param.Set("2021-03-04"))
+
+	require.Equal(t, []time.Time{
+		time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+	}, into)
+	require.Equal(t, `[2020-01-02, 2021-03-04]`, param.String())
+	require.False(t, param.IsDefault())
+
+	require.Error(t, param.Set("c"))
+
+	param.Clear()
+
+	require.Empty(t, into)
+	require.Equal(t, `[]`, param.String())
+	require.True(t, param.IsDefault())
+}
+
+func TestStringMapParam(t *testing.T) {
+	var into map[string]string
+	param := NewStringMap(&into, nil)
+
+	require.NoError(t, param.Set("team=core"))
+	require.NoError(t, param.Set("env=prod=eu"))
+	require.NoError(t, param.Set("empty="))
+
+	require.Equal(t, map[string]string{"team": "core", "env": "prod=eu", "empty": ""}, into)
+	require.Equal(t, `[empty="", env="prod=eu", team="core"]`, param.String())
+
+	require.EqualError(t, param.Set("team=infra"), `duplicate key "team"`)
+	require.Error(t, param.Set("team"))
+	require.Error(t, param.Set("=core"))
+	require.Equal(t, map[string]string{"team": "core", "env": "prod=eu", "empty": ""}, into)
+
+	require.False(t, param.IsDefault())
+
+	param.Clear()
+
+	require.Empty(t, into)
+	require.Equal(t, `[]`, param.String())
+	require.True(t, param.IsDefault())
+}
+
+func TestStringMapParamDoesNotAlterTheDefault(t *testing.T) {
+	var into map[string]string
+	def := map[string]string{"env": "dev"}
+	param := NewStringMap(&into, def)
+
+	param.Clear()
+	require.NoError(t, param.Set("env=prod"))
+
+	require.Equal(t, map[string]string{"env": "prod"}, into)
+	require.Equal(t, map[string]string{"env": "dev"}, def)
+}
+
+func TestIntMapParam(t *testing.T) {
+	var into map[string]int
+	param := NewIntMap(&into, nil)
+
+	require.NoError(t, param.Set("b=2"))
+	require.NoError(t, param.Set("a=-1"))
+
+	require.Equal(t, map[string]int{"a": -1, "b": 2}, into)
+	require.Equal(t, `[a=-1, b=2]`, param.String())
+
+	require.EqualError(t, param.Set("a=3"), `duplicate key "a"`)
+	require.Error(t, param.Set("c=x"))
+	require.Error(t, param.Set("c"))
+	require.Equal(t, map[string]int{"a": -1, "b": 2}, into)
+
+	require.False(t, param.IsDefault())
+
+	param.Clear()
+
+	require.Empty(t, into)
+	require.True(t, param.IsDefault())
+}
+
+func TestCounterParam(t *testing.T) {
+	var into int
+	param := NewCounter(&into, 0)
+
+	require.True(t, IsBool(param))
+	require.True(t, IsCounter(param))
+	require.False(t, IsCounter(NewBool(new(bool), false)))
+	require.True(t, param.IsDefault())
+
+	require.NoError(t, param.Set("true"))
+	require.NoError(t, param.Set("true"))
+	require.Equal(t, 2, into)
+
+	require.NoError(t, param.Set("3"))
+	require.Equal(t, 5, into)
+	require.Equal(t, "5", param.String())
+	require.False(t, param.IsDefault())
+
+	require.Error(t, param.Set("x"))
+	require.Error(t, param.Set("-2"))
+	require.Equal(t, 5, into)
+
+	require.NoError(t, param.Set("false"))
+	require.Equal(t, 0, into)
+
+	param.Set("2")
+	param.Clear()
+	require.Equal(t, 0, into)
+}
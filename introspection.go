@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"github.com/jawher/mow.cli/internal/container"
+	"github.com/jawher/mow.cli/internal/values"
+)
+
+/*
+CommandInfo describes a command and its sub commands, as returned by Cmd.Info and Cmd.Commands
+*/
+type CommandInfo struct {
+	// The command's name, i.e. the first of its aliases
+	Name string
+	// The names the command can be called with, e.g. remote and rmt
+	Aliases []string
+	// The command's short description
+	Desc string
+	// The command's long description, shown in its help message instead of Desc
+	LongDesc string
+	// The command's full path, e.g. "app remote add"
+	Path string
+	// The command's spec, the generated one if none was set
+	Spec string
+	// Set to true if the command is hidden from the help messages and completions
+	Hidden bool
+	// The command's options in declaration order
+	Options []OptionInfo
+	// The command's arguments in declaration order
+	Args []ArgInfo
+	// The command's sub commands in declaration order, including the hidden ones
+	Commands []CommandInfo
+}
+
+/*
+OptionInfo describes an option of a command, as returned by Cmd.Options
+*/
+type OptionInfo struct {
+	// The option names with their dashes, e.g. -f and --force
+	Names []string
+	// The option description as shown in help messages
+	Desc string
+	// The space separated list of environment variables names used to initialize the option
+	EnvVar string
+	// The option's initial value as shown in help messages, empty if Hidden is set
+	Default string
+	// Set to true if the option's value is not shown in the help messages, e.g. for a secret
+	Hidden bool
+	// The option's value type, e.g. bool, string, ints, duration or var for a VarOpt
+	Type string
+	// Set to true if the option is mandatory
+	Required bool
+	// The values the option accepts, any if empty
+	Choices []string
+	// Set to true if the option can be set to false with its negated names
+	Negatable bool
+	// The names which set a negatable option to false, e.g. --no-color
+	NegatedNames []string
+	// The value used when the option is given without a value, empty if a value is required
+	NoOptDefVal string
+}
+
+/*
+ArgInfo describes an argument of a command, as returned by Cmd.Args
+*/
+type ArgInfo struct {
+	// The argument name, e.g. SRC
+	Name string
+	// The argument description as shown in help messages
+	Desc string
+	// The space separated list of environment variables names used to initialize the argument
+	EnvVar string
+	// The argument's initial value as shown in help messages, empty if Hidden is set
+	Default string
+	// Set to true if the argument's value is not shown in the help messages, e.g. for a secret
+	Hidden bool
+	// The argument's value type, e.g. bool, string, ints, duration or var for a VarArg
+	Type string
+	// The values the argument accepts, any if empty
+	Choices []string
+}
+
+/*
+Name returns the command's name, i.e. the first of its aliases
+*/
+func (c *Cmd) Name() string {
+	return c.name
+}
+
+/*
+Aliases returns the names a sub command can be called with, e.g. remote and rmt
+*/
+func (c *Cmd) Aliases() []string {
+	return append([]string{}, c.aliases...)
+}
+
+/*
+Desc returns the command's short description
+*/
+func (c *Cmd) Desc() string {
+	return c.desc
+}
+
+/*
+Path returns the command's full path, e.g. "app remote add"
+*/
+func (c *Cmd) Path() string {
+	return c.path()
+}
+
+/*
+Info returns a description of the command and of its sub commands tree.
+The command and its sub commands get initialized first, which panics if one of their specs is invalid
+*/
+func (c *Cmd) Info() CommandInfo {
+	c.mustInit()
+	return CommandInfo{
+		Name:     c.name,
+		Aliases:  c.Aliases(),
+		Desc:     c.desc,
+		LongDesc: c.LongDesc,
+		Path:     c.path(),
+		Spec:     c.Spec,
+		Hidden:   c.Hidden,
+		Options:  c.Options(),
+		Args:     c.Args(),
+		Commands: c.Commands(),
+	}
+}
+
+/*
+Commands returns a description of the command's sub commands in declaration order, including the hidden ones.
+The command and its sub commands get initialized first, which panics if one of their specs is invalid
+*/
+func (c *Cmd) Commands() []CommandInfo {
+	c.mustInit()
+	res := make([]CommandInfo, len(c.commands))
+	for i, sub := range c.commands {
+		res[i] = sub.Info()
+	}
+	return res
+}
+
+/*
+Options returns a description of the command's options in declaration order.
+The command gets initialized first, which panics if its spec is invalid
+*/
+func (c *Cmd) Options() []OptionInfo {
+	c.mustInit()
+	res := make([]OptionInfo, len(c.options))
+	for i, opt := range c.options {
+		res[i] = OptionInfo{
+			Names:        append([]string{}, opt.Names...),
+			Desc:         opt.Desc,
+			EnvVar:       opt.EnvVar,
+			Default:      paramDefault(opt),
+			Hidden:       opt.HideValue,
+			Type:         paramType(opt),
+			Required:     opt.Required,
+			Choices:      copyStrings(opt.Choices),
+			Negatable:    opt.Negatable,
+			NegatedNames: opt.NegatedNames(),
+			NoOptDefVal:  opt.NoOptDefVal,
+		}
+	}
+	return res
+}
+
+/*
+Args returns a description of the command's arguments in declaration order.
+The command gets initialized first, which panics if its spec is invalid
+*/
+func (c *Cmd) Args() []ArgInfo {
+	c.mustInit()
+	res := make([]ArgInfo, len(c.args))
+	for i, arg := range c.args {
+		res[i] = ArgInfo{
+			Name:    arg.Name,
+			Desc:    arg.Desc,
+			EnvVar:  arg.EnvVar,
+			Default: paramDefault(arg),
+			Hidden:  arg.HideValue,
+			Type:    paramType(arg),
+			Choices: copyStrings(arg.Choices),
+		}
+	}
+	return res
+}
+
+func (c *Cmd) mustInit() {
+	if err := c.doInit(); err != nil {
+		panic(err)
+	}
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
+
+func paramDefault(con *container.Container) string {
+	if con.HideValue {
+		return ""
+	}
+	return con.DefaultValue
+}
+
+func paramType(con *container.Container) string {
+	switch con.Value.(type) {
+	case *values.BoolValue:
+		return "bool"
+	case *values.CounterValue:
+		return "count"
+	case *values.StringValue:
+		return "string"
+	case *values.IntValue:
+		return "int"
+	case *values.Float64Value:
+		return "float64"
+	case *values.StringsValue:
+		return "strings"
+	case *values.IntsValue:
+		return "ints"
+	case *values.Floats64Value:
+		return "floats64"
+	case *values.DurationValue:
+		return "duration"
+	case *values.DurationsValue:
+		return "durations"
+	case *values.TimeValue:
+		return "time"
+	case *values.TimesValue:
+		return "times"
+	case *values.StringMapValue:
+		return "string map"
+	case *values.IntMapValue:
+		return "int map"
+	default:
+		return "var"
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospection(t *testing.T) {
+	app := App("app", "App description")
+	app.BoolOpt("v verbose", false, "Verbose mode")
+	app.String(StringOpt{Name: "password", EnvVar: "PASSWORD PASS", Value: "s3cr3t", Secret: true})
+	app.Count(CountOpt{Name: "q"})
+
+	app.Command("remote rmt", "Manage remotes", func(cmd *Cmd) {
+		cmd.Command("add", "Add a remote", func(cmd *Cmd) {
+			cmd.IntsOpt("p ports", []int{80}, "")
+			cmd.DurationOpt("timeout", time.Second, "")
+			cmd.StringArg("NAME", "origin", "The remote name")
+			cmd.Var(VarArg{Name: "URL", Value: &intValue{}})
+		})
+	})
+	app.Command("debug", "", func(cmd *Cmd) {
+		cmd.Hidden = true
+	})
+
+	require.Equal(t, "app", app.Name())
+	require.Equal(t, "app", app.Path())
+	require.Equal(t, "App description", app.Desc())
+
+	require.Equal(t, []OptionInfo{
+		{Names: []string{"-v", "--verbose"}, Desc: "Verbose mode", Type: "bool"},
+		{Names: []string{"--password"}, EnvVar: "PASSWORD PASS", Hidden: true, Type: "string"},
+		{Names: []string{"-q"}, Type: "count"},
+	}, app.Options())
+	require.Empty(t, app.Args())
+
+	commands := app.Commands()
+	require.Len(t, commands, 2)
+
+	remote := commands[0]
+	require.Equal(t, "remote", remote.Name)
+	require.Equal(t, []string{"remote", "rmt"}, remote.Aliases)
+	require.Equal(t, "Manage remotes", remote.Desc)
+	require.Equal(t, "app remote", remote.Path)
+	require.False(t, remote.Hidden)
+
+	add := remote.Commands[0]
+	require.Equal(t, "app remote add", add.Path)
+	require.Equal(t, "[OPTIONS] NAME URL ", add.Spec, "the command should have been initialized")
+	require.Equal(t, []OptionInfo{
+		{Names: []string{"-p", "--ports"}, Default: "[80]", Type: "ints"},
+		{Names: []string{"--timeout"}, Default: "1s", Type: "duration"},
+	}, add.Options)
+	require.Equal(t, []ArgInfo{
+		{Name: "NAME", Desc: "The remote name", Default: `"origin"`, Type: "string"},
+		{Name: "URL", Default: "0", Type: "var"},
+	}, add.Args)
+	require.Empty(t, add.Commands)
+
+	require.True(t, commands[1].Hidden)
+	require.Equal(t, commands, app.Info().Commands)
+}
+
+func TestIntrospectionAttributes(t *testing.T) {
+	app := App("app", "")
+	app.Bool(BoolOpt{Name: "color", Value: true, Negatable: true})
+	app.String(StringOpt{Name: "format", Choices: []string{"json", "yaml"}, Required: true})
+	app.String(StringOpt{Name: "log", NoOptDefVal: "info"})
+	app.String(StringArg{Name: "MODE", Choices: []string{"fast", "slow"}})
+
+	require.Equal(t, []OptionInfo{
+		{Names: []string{"--color"}, Default: "true", Type: "bool", Negatable: true, NegatedNames: []string{"--no-color"}},
+		{Names: []string{"--format"}, Type: "string", Required: true, Choices: []string{"json", "yaml"}},
+		{Names: []string{"--log"}, Type: "string", NoOptDefVal: "info"},
+	}, app.Options())
+	require.Equal(t, []ArgInfo{
+		{Name: "MODE", Type: "string", Choices: []string{"fast", "slow"}},
+	}, app.Args())
+}
+
+func TestIntrospectionIsReadOnly(t *testing.T) {
+	app := App("app", "")
+	app.BoolOpt("v verbose", false, "")
+	app.Command("remote", "", func(cmd *Cmd) {})
+
+	app.Options()[0].Names[0] = "-x"
+	app.Commands()[0].Aliases[0] = "x"
+	app.Commands()[0].Name = "x"
+
+	require.Equal(t, []string{"-v", "--verbose"}, app.Options()[0].Names)
+	require.Equal(t, []string{"remote"}, app.Commands()[0].Aliases)
+	require.Equal(t, "remote", app.Commands()[0].Name)
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+/*
+GenerateManPages writes a roff man page (section 1) for the app and for each one of its visible commands into dir.
+
+The pages are named after the command path, e.g. the page of the "remote add" command of an app named "app"
+will be written to app-remote-add.1.
+*/
+func (cli *Cli) GenerateManPages(dir string) error {
+	version := ""
+	if cli.version != nil {
+		version = cli.version.version
+	}
+
+	return cli.walk(func(c *Cmd) error {
+		buf := &bytes.Buffer{}
+		c.writeManPage(buf, cli.name, version)
+
+		filename := filepath.Join(dir, c.pageName()+".1")
+		return ioutil.WriteFile(filename, buf.Bytes(), 0644)
+	})
+}
+
+// pageName returns the name of the command documentation page, e.g. app-remote-add
+func (c *Cmd) pageName() string {
+	return strings.Replace(c.path(), " ", "-", -1)
+}
+
+func (c *Cmd) writeManPage(w io.Writer, app, version string) {
+	name := c.pageName()
+
+	fmt.Fprintf(w, ".TH %s 1 \"\" %s %s\n", roffQuote(strings.ToUpper(name)), roffQuote(version), roffQuote(app+" manual"))
+
+	fmt.Fprint(w, ".SH NAME\n")
+	fmt.Fprintf(w, "%s", roffEscape(name))
+	if desc := firstLine(c.desc); desc != "" {
+		fmt.Fprintf(w, " \\- %s", roffEscape(desc))
+	}
+	fmt.Fprint(w, "\n")
+
+	fmt.Fprint(w, ".SH SYNOPSIS\n")
+	fmt.Fprintf(w, "\\fB%s\\fR", roffEscape(c.path()))
+	if usage := strings.TrimPrefix(c.usage(), c.path()); usage != "" {
+		fmt.Fprintf(w, "%s", roffEscape(usage))
+	}
+	fmt.Fprint(w, "\n")
+
+	desc := c.desc
+	if len(c.LongDesc) > 0 {
+		desc = c.LongDesc
+	}
+	if strings.TrimSpace(desc) != "" {
+		fmt.Fprint(w, ".SH DESCRIPTION\n")
+		writeRoffText(w, desc)
+	}
+
+	if len(c.args) > 0 {
+		fmt.Fprint(w, ".SH ARGUMENTS\n")
+		for _, arg := range c.args {
+			fmt.Fprint(w, ".TP\n")
+			fmt.Fprintf(w, "\\fB%s\\fR\n", roffEscape(arg.Name))
+			writeRoffText(w, formatDescForHelp(arg))
+		}
+	}
+
+	if len(c.options) > 0 {
+		fmt.Fprint(w, ".SH OPTIONS\n")
+		for _, opt := range c.options {
+			var names []string
+			for _, n := range append(append([]string{}, opt.Names...), opt.NegatedNames()...) {
+				names = append(names, fmt.Sprintf("\\fB%s\\fR", roffEscape(n)))
+			}
+			fmt.Fprint(w, ".TP\n")
+			fmt.Fprintf(w, "%s\n", strings.Join(names, ", "))
+			writeRoffText(w, formatDescForHelp(opt))
+		}
+	}
+
+	commands := c.visibleCommands()
+	if len(commands) > 0 {
+		fmt.Fprint(w, ".SH COMMANDS\n")
+		for _, sub := range commands {
+			fmt.Fprint(w, ".TP\n")
+			fmt.Fprintf(w, "\\fB%s\\fR\n", roffEscape(strings.Join(sub.aliases, ", ")))
+			writeRoffText(w, sub.desc)
+		}
+	}
+
+	var seeAlso []string
+	if len(c.parents) > 0 {
+		seeAlso = append(seeAlso, fmt.Sprintf("\\fB%s\\fR(1)", roffEscape(c.parentPageName())))
+	}
+	for _, sub := range commands {
+		seeAlso = append(seeAlso, fmt.Sprintf("\\fB%s\\fR(1)", roffEscape(sub.pageName())))
+	}
+	if len(seeAlso) > 0 {
+		fmt.Fprint(w, ".SH SEE ALSO\n")
+		fmt.Fprintf(w, "%s\n", strings.Join(seeAlso, ", "))
+	}
+}
+
+// writeRoffText writes a (possibly multi-line) text, with the empty lines starting new paragraphs
+func writeRoffText(w io.Writer, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			fmt.Fprint(w, ".PP\n")
+			continue
+		}
+		fmt.Fprintf(w, "%s\n", roffEscape(line))
+	}
+}
+
+// roffEscape escapes the characters having a special meaning in roff
+func roffEscape(s string) string {
+	s = strings.Replace(s, `\`, `\e`, -1)
+	s = strings.Replace(s, "-", `\-`, -1)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+func roffQuote(s string) string {
+	return `"` + strings.Replace(roffEscape(s), `"`, `\(dq`, -1) + `"`
+}
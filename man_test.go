@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func referenceTestApp() *Cli {
+	app := App("app", "App Desc")
+	app.LongDesc = "Longer App Desc\n\n.A paragraph starting with a dot"
+	app.Version("v version", "app 1.2.3")
+	app.Bool(BoolOpt{Name: "d debug", Value: false, EnvVar: "APP_DEBUG", Desc: "Enable debug logs"})
+
+	app.Command("remote r", "Manage remotes", func(cmd *Cmd) {
+		cmd.Command("add", "Add a remote", func(cmd *Cmd) {
+			cmd.Spec = "[-t=<branch>] NAME URL"
+			cmd.String(StringOpt{Name: "t track", Value: "master", Desc: "Branch to track"})
+			cmd.String(StringArg{Name: "NAME", Value: "", Desc: "The remote name"})
+			cmd.String(StringArg{Name: "URL", Value: "", EnvVar: "REMOTE_URL", Desc: `The remote url, e.g. C:\repo`})
+		})
+		cmd.Command("rm", "Remove a remote", func(cmd *Cmd) {
+			cmd.Hidden = true
+		})
+	})
+	return app
+}
+
+func TestGenerateManPages(t *testing.T) {
+	app := referenceTestApp()
+
+	dir, err := ioutil.TempDir("", "mow-man")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t,
+		app.GenerateManPages(dir))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	sort.Strings(names)
+	require.Equal(t, []string{"app-remote-add.1", "app-remote.1", "app.1"}, names)
+
+	for _, name := range names {
+		actual, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+
+		filename := filepath.Join("testdata", "man", name)
+
+		if *genGolden {
+			require.NoError(t,
+				ioutil.WriteFile(filename, actual, 0644))
+		}
+
+		expected, err := ioutil.ReadFile(filename)
+		require.NoError(t, err, "Failed to read the expected man page from %s", filename)
+
+		require.Equal(t, string(expected), string(actual))
+	}
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppWithStringMapOption(t *testing.T) {
+	cases := []struct {
+		args             []string
+		expectedOptValue map[string]string
+	}{
+		{[]string{"app"}, map[string]string{"env": "dev"}},
+		{[]string{"app", "-l", "env=prod"}, map[string]string{"env": "prod"}},
+		{[]string{"app", "-l", "env=prod", "--label=team=core", "-lk=v"}, map[string]string{"env": "prod", "team": "core", "k": "v"}},
+	}
+
+	for _, cas := range cases {
+		runAppAndCheckValue(t, "short", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.StringMapOpt("l label", map[string]string{"env": "dev"}, "")
+		})
+		runAppAndCheckValue(t, "struct", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.StringMap(StringMapOpt{
+				Name:  "l label",
+				Value: map[string]string{"env": "dev"},
+			})
+		})
+		runAppAndCheckValue(t, "short-ptr", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			var val map[string]string
+			app.StringMapOptPtr(&val, "l label", map[string]string{"env": "dev"}, "")
+			return &val
+		})
+		runAppAndCheckValue(t, "struct-ptr", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			var val map[string]string
+			app.StringMapPtr(&val, StringMapOpt{
+				Name:  "l label",
+				Value: map[string]string{"env": "dev"},
+			})
+			return &val
+		})
+	}
+}
+
+func TestAppWithIntMapOption(t *testing.T) {
+	cases := []struct {
+		args             []string
+		expectedOptValue map[string]int
+	}{
+		{[]string{"app"}, map[string]int{"cpu": 1}},
+		{[]string{"app", "-q", "cpu=2", "--quota", "mem=512"}, map[string]int{"cpu": 2, "mem": 512}},
+	}
+
+	for _, cas := range cases {
+		runAppAndCheckValue(t, "short", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.IntMapOpt("q quota", map[string]int{"cpu": 1}, "")
+		})
+		runAppAndCheckValue(t, "struct-ptr", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			var val map[string]int
+			app.IntMapPtr(&val, IntMapOpt{
+				Name:  "q quota",
+				Value: map[string]int{"cpu": 1},
+			})
+			return &val
+		})
+	}
+}
+
+func TestAppWithMapArgs(t *testing.T) {
+	runAppAndCheckValue(t, "string-map", []string{"app", "a=1", "b=2"}, map[string]string{"a": "1", "b": "2"}, func(app *Cli) interface{} {
+		app.Spec = "ARG..."
+		return app.StringMapArg("ARG", nil, "")
+	})
+	runAppAndCheckValue(t, "int-map-ptr", []string{"app", "a=1", "b=2"}, map[string]int{"a": 1, "b": 2}, func(app *Cli) interface{} {
+		app.Spec = "ARG..."
+		var val map[string]int
+		app.IntMapArgPtr(&val, "ARG", nil, "")
+		return &val
+	})
+	runAppAndCheckValue(t, "string-map-struct", []string{"app", "a=1"}, map[string]string{"a": "1"}, func(app *Cli) interface{} {
+		return app.StringMap(StringMapArg{Name: "ARG"})
+	})
+}
+
+func TestMapValuesFromEnv(t *testing.T) {
+	defer setAndRestoreEnv(map[string]string{
+		"LABELS": "env=prod, team=core",
+		"QUOTAS": "cpu=2,cpu=4",
+	})()
+
+	app := App("app", "")
+	labels := app.StringMap(StringMapOpt{Name: "label", EnvVar: "LABELS"})
+	quotas := app.IntMap(IntMapOpt{Name: "quota", EnvVar: "QUOTAS"})
+
+	called := false
+	app.Action = func() {
+		called = true
+		require.Equal(t, map[string]string{"env": "prod", "team": "core"}, *labels)
+		require.Empty(t, *quotas, "an env var with duplicate keys should be ignored")
+	}
+
+	require.NoError(t,
+		app.Run([]string{"app"}))
+	require.True(t, called)
+}
+
+func TestMapDuplicateKey(t *testing.T) {
+	defer suppressOutput()()
+
+	exitCalled := false
+	defer exitShouldBeCalledWith(t, 2, &exitCalled)()
+
+	app := App("app", "")
+	app.StringMapOpt("l label", nil, "")
+	app.Action = func() {}
+
+	err := app.Run([]string{"app", "-l", "env=prod", "-l", "env=dev"})
+
+	var invalid *InvalidValueError
+	require.True(t, errors.As(err, &invalid))
+	require.Equal(t, 4, invalid.Index)
+	require.EqualError(t, err, `invalid value "env=dev" for --label: duplicate key "env"`)
+	require.True(t, exitCalled)
+}
+
+func TestMapValuesDefaultsInHelp(t *testing.T) {
+	var out string
+	defer captureAndRestoreOutput(nil, &out)()
+
+	app := App("app", "")
+	app.StringMapOpt("label", map[string]string{"team": "core", "env": "prod"}, "The labels")
+	app.IntMapOpt("quota", map[string]int{"mem": 512, "cpu": 2}, "The quotas")
+	app.IntMapOpt("empty", nil, "Not shown when empty")
+
+	app.PrintHelp()
+
+	require.Contains(t, out, `The labels (default [env="prod", team="core"])`)
+	require.Contains(t, out, `The quotas (default [cpu=2, mem=512])`)
+	require.NotContains(t, out, "Not shown when empty (default")
+}
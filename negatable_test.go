@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegatableBoolOpt(t *testing.T) {
+	cases := []struct {
+		args     []string
+		env      map[string]string
+		expected bool
+	}{
+		{args: []string{"app"}, expected: true},
+		{args: []string{"app", "--color"}, expected: true},
+		{args: []string{"app", "--no-color"}, expected: false},
+		{args: []string{"app", "-c", "--no-color"}, expected: false},
+		{args: []string{"app", "--no-color", "--color"}, expected: true},
+		{args: []string{"app", "--no-color"}, env: map[string]string{"COLOR": "true"}, expected: false},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q %v", cas.args, cas.env), func(t *testing.T) {
+			defer exitShouldNotCalled(t)()
+			defer setAndRestoreEnv(cas.env)()
+
+			app := App("app", "")
+			color := app.Bool(BoolOpt{Name: "c color", Value: true, EnvVar: "COLOR", Negatable: true})
+
+			called := false
+			app.Action = func() {
+				called = true
+			}
+
+			require.NoError(t, app.Run(cas.args))
+			require.True(t, called)
+			require.Equal(t, cas.expected, *color)
+		})
+	}
+}
+
+func TestNegatableBoolOptRejectsValue(t *testing.T) {
+	defer suppressOutput()()
+
+	called := false
+	defer exitShouldBeCalledWith(t, 2, &called)()
+
+	app := App("app", "")
+	app.Bool(BoolOpt{Name: "color", Negatable: true})
+	app.Action = func() {}
+
+	app.Run([]string{"app", "--no-color=true"})
+	require.True(t, called, "exit should have been called")
+}
+
+func TestNegatableBoolOptDuplicateName(t *testing.T) {
+	app := App("app", "")
+	app.Bool(BoolOpt{Name: "color", Negatable: true})
+
+	require.Panics(t, func() {
+		app.BoolOpt("no-color", false, "")
+	})
+}
+
+func TestNegatableBoolOptHelp(t *testing.T) {
+	var out, stdErr string
+	defer captureAndRestoreOutput(&out, &stdErr)()
+	defer exitShouldBeCalledWith(t, 0, new(bool))()
+
+	app := App("app", "")
+	app.Bool(BoolOpt{Name: "c color", Value: true, Desc: "Colorize the output", Negatable: true})
+	app.Run([]string{"app", "-h"})
+
+	require.Contains(t, stdErr, "-c, --[no-]color")
+	require.Contains(t, stdErr, "Colorize the output")
+}
+
+func TestNegatableBoolOptCompletion(t *testing.T) {
+	app := App("app", "")
+	app.Bool(BoolOpt{Name: "color", Negatable: true})
+
+	require.NoError(t, app.doInit())
+	require.Contains(t, app.optionNames(), "--color")
+	require.Contains(t, app.optionNames(), "--no-color")
+}
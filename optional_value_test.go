@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionalValueOpt(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+		arg      string
+	}{
+		{args: []string{"app"}, expected: "auto"},
+		{args: []string{"app", "--color"}, expected: "always"},
+		{args: []string{"app", "--color=never"}, expected: "never"},
+		{args: []string{"app", "--color", "never"}, expected: "always", arg: "never"},
+		{args: []string{"app", "-c"}, expected: "always"},
+		{args: []string{"app", "-cnever"}, expected: "never"},
+		{args: []string{"app", "-c", "never"}, expected: "always", arg: "never"},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			defer exitShouldNotCalled(t)()
+
+			app := App("app", "")
+			app.Spec = "[-c] [ARG]"
+			color := app.String(StringOpt{Name: "c color", Value: "auto", NoOptDefVal: "always"})
+			arg := app.StringArg("ARG", "", "")
+
+			called := false
+			app.Action = func() {
+				called = true
+			}
+
+			require.NoError(t, app.Run(cas.args))
+			require.True(t, called)
+			require.Equal(t, cas.expected, *color)
+			require.Equal(t, cas.arg, *arg)
+		})
+	}
+}
+
+func TestOptionalValueIntOpt(t *testing.T) {
+	runAppAndCheckValue(t, "no value", []string{"app", "--level"}, 3, func(app *Cli) interface{} {
+		return app.Int(IntOpt{Name: "level", Value: 1, NoOptDefVal: "3"})
+	})
+	runAppAndCheckValue(t, "value", []string{"app", "--level=5"}, 5, func(app *Cli) interface{} {
+		return app.Int(IntOpt{Name: "level", Value: 1, NoOptDefVal: "3"})
+	})
+}
+
+func TestOptionalValueOptDoesNotTakeNextArg(t *testing.T) {
+	defer suppressOutput()()
+
+	called := false
+	defer exitShouldBeCalledWith(t, 2, &called)()
+
+	app := App("app", "")
+	app.String(StringOpt{Name: "color", NoOptDefVal: "always"})
+	app.Action = func() {}
+
+	app.Run([]string{"app", "--color", "never"})
+	require.True(t, called, "exit should have been called")
+}
+
+func TestOptionalValueOptHelp(t *testing.T) {
+	var out, stdErr string
+	defer captureAndRestoreOutput(&out, &stdErr)()
+	defer exitShouldBeCalledWith(t, 0, new(bool))()
+
+	app := App("app", "")
+	app.String(StringOpt{Name: "c color", Value: "auto", Desc: "Colorize the output", NoOptDefVal: "always"})
+	app.String(StringOpt{Name: "t", Desc: "Theme", NoOptDefVal: "dark"})
+	app.Run([]string{"app", "-h"})
+
+	require.Contains(t, stdErr, "-c, --color[=COLOR]")
+	require.Contains(t, stdErr, "Colorize the output (always if no value is given) (default \"auto\")")
+	require.Contains(t, stdErr, "-t[=VALUE]")
+}
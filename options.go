@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jawher/mow.cli/internal/container"
 	"github.com/jawher/mow.cli/internal/values"
@@ -24,6 +25,10 @@ type BoolOpt struct {
 	HideValue bool
 	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// Set to true to also accept the long names prefixed with no-, e.g. --no-color, to set the option to false
+	Negatable bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(bool) error
 }
 
 func (o BoolOpt) value(into *bool) (flag.Value, *bool) {
@@ -48,6 +53,20 @@ type StringOpt struct {
 	HideValue bool
 	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// The values this option accepts, any other value is rejected. Also used as the completion candidates if Complete is not set
+	Choices []string
+	// Set to true if this option holds a secret, e.g. a password: its value is never shown in the help messages nor in the config dumps,
+	// and if one of its env vars VAR is not set, the value is read from the file named by VAR_FILE, with the trailing newlines stripped
+	Secret bool
+	// The value used when the option is given without a value, e.g. `--color` instead of `--color=always`.
+	// When set, the option's value becomes optional and can only be passed in the --name=value or -nvalue forms
+	NoOptDefVal string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(string) error
 }
 
 func (o StringOpt) value(into *string) (flag.Value, *string) {
@@ -72,6 +91,15 @@ type IntOpt struct {
 	HideValue bool
 	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// The value used when the option is given without a value, e.g. `--color` instead of `--color=always`.
+	// When set, the option's value becomes optional and can only be passed in the --name=value or -nvalue forms
+	NoOptDefVal string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(int) error
 }
 
 func (o IntOpt) value(into *int) (flag.Value, *int) {
@@ -96,6 +124,15 @@ type Float64Opt struct {
 	HideValue bool
 	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// The value used when the option is given without a value, e.g. `--color` instead of `--color=always`.
+	// When set, the option's value becomes optional and can only be passed in the --name=value or -nvalue forms
+	NoOptDefVal string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(float64) error
 }
 
 func (o Float64Opt) value(into *float64) (flag.Value, *float64) {
@@ -121,6 +158,14 @@ type StringsOpt struct {
 	HideValue bool
 	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// The values this option accepts, any other value is rejected. Also used as the completion candidates if Complete is not set
+	Choices []string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func([]string) error
 }
 
 func (o StringsOpt) value(into *[]string) (flag.Value, *[]string) {
@@ -146,6 +191,12 @@ type IntsOpt struct {
 	HideValue bool
 	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func([]int) error
 }
 
 func (o IntsOpt) value(into *[]int) (flag.Value, *[]int) {
@@ -172,6 +223,12 @@ type Floats64Opt struct {
 	HideValue bool
 	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func([]float64) error
 }
 
 func (o Floats64Opt) value(into *[]float64) (flag.Value, *[]float64) {
@@ -182,6 +239,224 @@ func (o Floats64Opt) value(into *[]float64) (flag.Value, *[]float64) {
 
 }
 
+// DurationOpt describes a duration option
+type DurationOpt struct {
+	// A space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+	// The one letter names will then be called with a single dash (short option), the others with two (long options).
+	Name string
+	// The option description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this option
+	EnvVar string
+	// The option's initial value
+	Value time.Duration
+	// A boolean to display or not the current value of the option in the help message
+	HideValue bool
+	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// The value used when the option is given without a value, e.g. `--color` instead of `--color=always`.
+	// When set, the option's value becomes optional and can only be passed in the --name=value or -nvalue forms
+	NoOptDefVal string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(time.Duration) error
+}
+
+func (o DurationOpt) value(into *time.Duration) (flag.Value, *time.Duration) {
+	if into == nil {
+		into = new(time.Duration)
+	}
+	return values.NewDuration(into, o.Value), into
+}
+
+// DurationsOpt describes a duration slice option
+type DurationsOpt struct {
+	// A space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+	// The one letter names will then be called with a single dash (short option), the others with two (long options).
+	Name string
+	// The option description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this option.
+	// The env variable should contain a comma separated list of values
+	EnvVar string
+	// The option's initial value
+	Value []time.Duration
+	// A boolean to display or not the current value of the option in the help message
+	HideValue bool
+	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func([]time.Duration) error
+}
+
+func (o DurationsOpt) value(into *[]time.Duration) (flag.Value, *[]time.Duration) {
+	if into == nil {
+		into = new([]time.Duration)
+	}
+	return values.NewDurations(into, o.Value), into
+}
+
+// TimeOpt describes a time option
+type TimeOpt struct {
+	// A space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+	// The one letter names will then be called with a single dash (short option), the others with two (long options).
+	Name string
+	// The option description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this option
+	EnvVar string
+	// The option's initial value
+	Value time.Time
+	// The layout used to parse and format the option values, as accepted by time.Parse (time.RFC3339 if empty)
+	Layout string
+	// A boolean to display or not the current value of the option in the help message
+	HideValue bool
+	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(time.Time) error
+}
+
+func (o TimeOpt) value(into *time.Time) (flag.Value, *time.Time) {
+	if into == nil {
+		into = new(time.Time)
+	}
+	return values.NewTime(into, o.Value, o.Layout), into
+}
+
+// TimesOpt describes a time slice option
+type TimesOpt struct {
+	// A space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+	// The one letter names will then be called with a single dash (short option), the others with two (long options).
+	Name string
+	// The option description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this option.
+	// The env variable should contain a comma separated list of values
+	EnvVar string
+	// The option's initial value
+	Value []time.Time
+	// The layout used to parse and format the option values, as accepted by time.Parse (time.RFC3339 if empty)
+	Layout string
+	// A boolean to display or not the current value of the option in the help message
+	HideValue bool
+	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func([]time.Time) error
+}
+
+func (o TimesOpt) value(into *[]time.Time) (flag.Value, *[]time.Time) {
+	if into == nil {
+		into = new([]time.Time)
+	}
+	return values.NewTimes(into, o.Value, o.Layout), into
+}
+
+// StringMapOpt describes a string map option
+type StringMapOpt struct {
+	// A space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+	// The one letter names will then be called with a single dash (short option), the others with two (long options).
+	Name string
+	// The option description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this option.
+	// The env variable should contain a comma separated list of key=value pairs
+	EnvVar string
+	// The option's initial value
+	Value map[string]string
+	// A boolean to display or not the current value of the option in the help message
+	HideValue bool
+	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(map[string]string) error
+}
+
+func (o StringMapOpt) value(into *map[string]string) (flag.Value, *map[string]string) {
+	if into == nil {
+		into = new(map[string]string)
+	}
+	return values.NewStringMap(into, o.Value), into
+}
+
+// IntMapOpt describes an int map option
+type IntMapOpt struct {
+	// A space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+	// The one letter names will then be called with a single dash (short option), the others with two (long options).
+	Name string
+	// The option description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this option.
+	// The env variable should contain a comma separated list of key=value pairs
+	EnvVar string
+	// The option's initial value
+	Value map[string]int
+	// A boolean to display or not the current value of the option in the help message
+	HideValue bool
+	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(map[string]int) error
+}
+
+func (o IntMapOpt) value(into *map[string]int) (flag.Value, *map[string]int) {
+	if into == nil {
+		into = new(map[string]int)
+	}
+	return values.NewIntMap(into, o.Value), into
+}
+
+// CountOpt describes an option counting its occurrences, e.g. -vvv
+type CountOpt struct {
+	// A space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+	// The one letter names will then be called with a single dash (short option), the others with two (long options).
+	Name string
+	// The option description as will be shown in help messages
+	Desc string
+	// A space separated list of environment variables names to be used to initialize this option.
+	// The env variable should contain a number
+	EnvVar string
+	// The option's initial value
+	Value int
+	// A boolean to display or not the current value of the option in the help message
+	HideValue bool
+	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
+	SetByUser *bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(int) error
+}
+
+func (o CountOpt) value(into *int) (flag.Value, *int) {
+	if into == nil {
+		into = new(int)
+	}
+	return values.NewCounter(into, o.Value), into
+}
+
 // VarOpt describes an option where the type and format of the value is controlled by the developer
 type VarOpt struct {
 	// A space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
@@ -197,6 +472,15 @@ type VarOpt struct {
 	HideValue bool
 	// Set to true if this option was set by the user (as opposed to being set from env or not set at all)
 	SetByUser *bool
+	// A function returning the completion candidates for a value starting with prefix, used by the shell completion
+	Complete func(prefix string) []string
+	// The value used when the option is given without a value, e.g. `--color` instead of `--color=always`.
+	// When set, the option's value becomes optional and can only be passed in the --name=value or -nvalue forms
+	NoOptDefVal string
+	// Set to true to make this option mandatory unless a custom spec lists it as optional: it must then be set in the call arguments, from an env var or from a config file
+	Required bool
+	// A function validating the option's value once set from the call arguments, an env var or a config file: an error makes the call fail with a usage error
+	Validate func(flag.Value) error
 }
 
 func (o VarOpt) value() flag.Value {
@@ -441,6 +725,254 @@ func (c *Cmd) Floats64OptPtr(into *[]float64, name string, value []float64, desc
 	})
 }
 
+/*
+DurationOpt defines a duration option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The result should be stored in a variable (a pointer to a time.Duration) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) DurationOpt(name string, value time.Duration, desc string) *time.Duration {
+	return c.Duration(DurationOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+DurationOptPtr defines a duration option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The into parameter points to a variable (a pointer to a time.Duration) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) DurationOptPtr(into *time.Duration, name string, value time.Duration, desc string) {
+	c.DurationPtr(into, DurationOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+DurationsOpt defines a duration slice option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The result should be stored in a variable (a pointer to a time.Duration slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) DurationsOpt(name string, value []time.Duration, desc string) *[]time.Duration {
+	return c.Durations(DurationsOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+DurationsOptPtr defines a duration slice option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The into parameter points to a variable (a pointer to a time.Duration slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) DurationsOptPtr(into *[]time.Duration, name string, value []time.Duration, desc string) {
+	c.DurationsPtr(into, DurationsOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+TimeOpt defines a time option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The values are parsed and formatted using the time.RFC3339 layout. Use the TimeOpt struct to specify another layout.
+
+The result should be stored in a variable (a pointer to a time.Time) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) TimeOpt(name string, value time.Time, desc string) *time.Time {
+	return c.Time(TimeOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+TimeOptPtr defines a time option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The values are parsed and formatted using the time.RFC3339 layout. Use the TimeOpt struct to specify another layout.
+
+The into parameter points to a variable (a pointer to a time.Time) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) TimeOptPtr(into *time.Time, name string, value time.Time, desc string) {
+	c.TimePtr(into, TimeOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+TimesOpt defines a time slice option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The values are parsed and formatted using the time.RFC3339 layout. Use the TimesOpt struct to specify another layout.
+
+The result should be stored in a variable (a pointer to a time.Time slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) TimesOpt(name string, value []time.Time, desc string) *[]time.Time {
+	return c.Times(TimesOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+TimesOptPtr defines a time slice option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The values are parsed and formatted using the time.RFC3339 layout. Use the TimesOpt struct to specify another layout.
+
+The into parameter points to a variable (a pointer to a time.Time slice) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) TimesOptPtr(into *[]time.Time, name string, value []time.Time, desc string) {
+	c.TimesPtr(into, TimesOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+StringMapOpt defines a string map option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The result should be stored in a variable (a pointer to a string map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) StringMapOpt(name string, value map[string]string, desc string) *map[string]string {
+	return c.StringMap(StringMapOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+StringMapOptPtr defines a string map option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The into parameter points to a variable (a pointer to a string map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) StringMapOptPtr(into *map[string]string, name string, value map[string]string, desc string) {
+	c.StringMapPtr(into, StringMapOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+IntMapOpt defines an int map option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The result should be stored in a variable (a pointer to an int map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) IntMapOpt(name string, value map[string]int, desc string) *map[string]int {
+	return c.IntMap(IntMapOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+IntMapOptPtr defines an int map option on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `f force` and *NOT* `-f --force`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+
+The into parameter points to a variable (a pointer to an int map) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) IntMapOptPtr(into *map[string]int, name string, value map[string]int, desc string) {
+	c.IntMapPtr(into, IntMapOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+CountOpt defines an option counting its occurrences on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `v verbose` and *NOT* `-v --verbose`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+Every occurrence of the option increments the count, including in folded short options, e.g. `-vvv` counts 3.
+
+The result should be stored in a variable (a pointer to an int) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) CountOpt(name string, value int, desc string) *int {
+	return c.Count(CountOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
+/*
+CountOptPtr defines an option counting its occurrences on the command c named `name`, with an initial value of `value` and a description of `desc` which will be used in help messages.
+
+The name is a space separated list of the option names *WITHOUT* the dashes, e.g. `v verbose` and *NOT* `-v --verbose`.
+The one letter names will then be called with a single dash (short option), the others with two (long options).
+
+Every occurrence of the option increments the count, including in folded short options, e.g. `-vvv` counts 3.
+
+The into parameter points to a variable (a pointer to an int) which will be populated when the app is run and the call arguments get parsed
+*/
+func (c *Cmd) CountOptPtr(into *int, name string, value int, desc string) {
+	c.CountPtr(into, CountOpt{
+		Name:  name,
+		Value: value,
+		Desc:  desc,
+	})
+}
+
 /*
 VarOpt defines an option where the type and format is controlled by the developer.
 
@@ -468,12 +1000,22 @@ func mkOptStrs(optName string) []string {
 
 func (c *Cmd) mkOpt(opt container.Container) {
 	opt.DefaultValue = values.DefaultValue(opt.Value)
-	opt.ValueSetFromEnv = values.SetFromEnv(opt.Value, opt.EnvVar)
+	if opt.Secret {
+		opt.HideValue = true
+		opt.ValueEnvVar = values.SetFromSecretEnv(opt.Value, opt.EnvVar, opt.Check)
+	} else {
+		opt.ValueEnvVar = values.SetFromEnv(opt.Value, opt.EnvVar, opt.Check)
+	}
+	opt.ValueSetFromEnv = opt.ValueEnvVar != ""
+	if opt.Complete == nil && len(opt.Choices) > 0 {
+		opt.Complete = completeChoices(opt.Choices)
+	}
 
 	opt.Names = mkOptStrs(opt.Name)
+	c.setFromConfig(&opt)
 
 	c.options = append(c.options, &opt)
-	for _, name := range opt.Names {
+	for _, name := range append(append([]string{}, opt.Names...), opt.NegatedNames()...) {
 		if _, found := c.optionsIdx[name]; found {
 			panic(fmt.Sprintf("duplicate option name %q", name))
 		}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredOpt(t *testing.T) {
+	cases := []struct {
+		spec     string
+		args     []string
+		env      map[string]string
+		expected error
+	}{
+		{args: []string{"app", "--name", "x", "src"}},
+		{args: []string{"app", "-v", "--name=x", "src"}},
+		{args: []string{"app", "src"}, env: map[string]string{"NAME": "x"}},
+		{args: []string{"app", "src"}, expected: &MissingOptionError{Command: "app", Option: "--name"}},
+		{args: []string{"app", "-v", "src"}, expected: &MissingOptionError{Command: "app", Option: "--name"}},
+		{spec: "[OPTIONS] SRC", args: []string{"app", "--name", "x", "src"}},
+		{spec: "[OPTIONS] SRC", args: []string{"app", "src"}, expected: &MissingOptionError{Command: "app", Option: "--name"}},
+		{spec: "[-v] -n SRC", args: []string{"app", "-n", "x", "src"}},
+		{spec: "[-v] -n SRC", args: []string{"app", "src"}, env: map[string]string{"NAME": "x"}},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q %q %v", cas.spec, cas.args, cas.env), func(t *testing.T) {
+			defer suppressOutput()()
+			defer setAndRestoreEnv(cas.env)()
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+			app.Spec = cas.spec
+			app.BoolOpt("v verbose", false, "")
+			name := app.String(StringOpt{Name: "n name", EnvVar: "NAME", Required: true})
+			app.StringArg("SRC", "", "")
+
+			called := false
+			app.Action = func() {
+				called = true
+			}
+
+			err := app.Run(cas.args)
+			require.Equal(t, cas.expected, err)
+			require.Equal(t, cas.expected == nil, called)
+			if cas.expected == nil {
+				require.Equal(t, "x", *name)
+			}
+		})
+	}
+}
+
+func TestRequiredOptError(t *testing.T) {
+	require.EqualError(t, &MissingOptionError{Command: "app", Option: "--name"}, "missing required option --name")
+}
+
+func TestRequiredOptHelp(t *testing.T) {
+	var out, stdErr string
+	defer captureAndRestoreOutput(&out, &stdErr)()
+	defer exitShouldBeCalledWith(t, 0, new(bool))()
+
+	app := App("app", "")
+	app.String(StringOpt{Name: "n name", Desc: "The name", Required: true})
+	app.Run([]string{"app", "-h"})
+
+	require.Contains(t, stdErr, "The name (required)")
+}
+
+func TestRequiredOptOptionalInSpec(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	app := App("app", "")
+	app.Spec = "[-n]"
+	name := app.String(StringOpt{Name: "n name", Value: "default", Required: true})
+
+	called := false
+	app.Action = func() {
+		called = true
+	}
+
+	require.NoError(t, app.Run([]string{"app"}))
+	require.True(t, called)
+	require.Equal(t, "default", *name)
+}
+
+func TestRequiredOptUsage(t *testing.T) {
+	var out, stdErr string
+	defer captureAndRestoreOutput(&out, &stdErr)()
+	defer exitShouldBeCalledWith(t, 0, new(bool))()
+
+	app := App("app", "")
+	app.BoolOpt("v verbose", false, "")
+	app.String(StringOpt{Name: "n name", Required: true})
+	app.StringArg("SRC", "", "")
+	app.Run([]string{"app", "-h"})
+
+	require.Contains(t, stdErr, "Usage: app --name=<name> [OPTIONS] SRC\n")
+}
+
+func TestRequiredOptDocs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mow-docs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	app := App("app", "")
+	app.String(StringOpt{Name: "n name", Desc: "The name", Required: true})
+
+	require.NoError(t, app.GenerateMarkdownDocs(dir))
+	require.NoError(t, app.GenerateHTMLDocs(dir))
+
+	md, err := ioutil.ReadFile(filepath.Join(dir, "app.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(md), "| The name (required) |")
+
+	html, err := ioutil.ReadFile(filepath.Join(dir, "app.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(html), "<td>The name (required)</td>")
+}
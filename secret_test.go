@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretFromFile(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "password")
+	require.NoError(t, ioutil.WriteFile(file, []byte("hunter2\n"), 0600))
+	defer setAndRestoreEnv(map[string]string{"DB_PASSWORD": "", "DB_PASSWORD_FILE": file})()
+
+	app := App("app", "")
+	app.Spec = "[--password] [--plain] [PASSWORD]"
+	password := app.String(StringOpt{Name: "password", EnvVar: "DB_PASSWORD", Secret: true})
+	plain := app.String(StringOpt{Name: "plain", EnvVar: "DB_PASSWORD"})
+	arg := app.String(StringArg{Name: "PASSWORD", EnvVar: "DB_PASSWORD", Secret: true})
+
+	app.Action = func() {
+		require.Equal(t, "hunter2", *password)
+		require.Equal(t, "", *plain)
+		require.Equal(t, "hunter2", *arg)
+		require.Equal(t, Source{Kind: SourceEnv, EnvVar: "DB_PASSWORD_FILE"}, app.SourceOf("password"))
+	}
+
+	require.NoError(t, app.Run([]string{"app"}))
+}
+
+func TestSecretIsHidden(t *testing.T) {
+	defer setAndRestoreEnv(map[string]string{"TOKEN": "from-env"})()
+
+	var out, stdErr string
+	defer captureAndRestoreOutput(&out, &stdErr)()
+
+	app := App("app", "")
+	app.PrintConfigOption("print-config")
+	app.String(StringOpt{Name: "token", Value: "default-token", EnvVar: "TOKEN", Desc: "The API token", Secret: true})
+
+	app.PrintHelp()
+	require.Contains(t, stdErr, "The API token (env $TOKEN, $TOKEN_FILE)\n")
+	require.NotContains(t, stdErr, "default-token")
+
+	exitCalled := false
+	defer exitShouldBeCalledWith(t, 0, &exitCalled)()
+
+	require.NoError(t, app.Run([]string{"app", "--print-config"}))
+	require.True(t, exitCalled)
+	require.Contains(t, out, "--token  ******  env $TOKEN")
+	require.NotContains(t, out, "from-env")
+}
+
+func TestSecretIsMaskedInErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "token")
+	require.NoError(t, ioutil.WriteFile(file, []byte("bad-from-file\n"), 0600))
+
+	errInvalid := errors.New("invalid token")
+
+	cases := []struct {
+		args     []string
+		env      map[string]string
+		expected error
+	}{
+		{
+			args:     []string{"app", "--token", "bad-from-argv"},
+			expected: &InvalidValueError{Command: "app", Index: 2, Name: "--token", Value: "******", Err: errInvalid},
+		},
+		{
+			args:     []string{"app"},
+			env:      map[string]string{"TOKEN": "bad-from-env"},
+			expected: &InvalidValueError{Command: "app", Index: -1, Name: "--token", Value: "******", Err: errInvalid},
+		},
+		{
+			args:     []string{"app"},
+			env:      map[string]string{"TOKEN": "", "TOKEN_FILE": file},
+			expected: &InvalidValueError{Command: "app", Index: -1, Name: "--token", Value: "******", Err: errInvalid},
+		},
+		{
+			args:     []string{"app", "--level", "bad-level"},
+			expected: &InvalidValueError{Command: "app", Index: 2, Name: "--level", Value: "******", Err: errInvalid},
+		},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q %v", cas.args, cas.env), func(t *testing.T) {
+			defer suppressOutput()()
+			defer setAndRestoreEnv(cas.env)()
+
+			invalid := func(string) error {
+				return errInvalid
+			}
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+			app.String(StringOpt{Name: "token", EnvVar: "TOKEN", Secret: true, Validate: invalid})
+			app.String(StringOpt{Name: "level", HideValue: true, Validate: invalid})
+			app.Action = func() {}
+
+			err := app.Run(cas.args)
+			require.Equal(t, cas.expected, err)
+			require.NotContains(t, err.Error(), "bad-")
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const defaultSignalExitCode = 130
+
+// handleSignals returns a context which is cancelled on the first SIGINT or SIGTERM, and exits the app on the second one.
+// The returned function stops the signals handling
+func (cli *Cli) handleSignals(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+
+	exitCode := cli.SignalExitCode
+	if exitCode == 0 {
+		exitCode = defaultSignalExitCode
+	}
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-done:
+			return
+		}
+
+		select {
+		case <-signals:
+			exiter(exitCode)
+		case <-done:
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		close(done)
+		cancel()
+	}
+}
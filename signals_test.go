@@ -0,0 +1,105 @@
+//go:build !windows
+// +build !windows
+
+package cli
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sendSignal(t *testing.T, sig syscall.Signal) {
+	require.NoError(t, syscall.Kill(syscall.Getpid(), sig))
+}
+
+func waitForCancellation(t *testing.T, ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the context should have been cancelled")
+	}
+}
+
+func TestHandleSignalsCancelsTheContext(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	for _, sig := range []syscall.Signal{syscall.SIGINT, syscall.SIGTERM} {
+		afterCalled := false
+
+		app := App("app", "")
+		app.HandleSignals = true
+		app.Command("c", "", func(cmd *Cmd) {
+			cmd.ActionE = func(ctx context.Context) error {
+				require.Equal(t, ctx, cmd.Context())
+				sendSignal(t, sig)
+				waitForCancellation(t, ctx)
+				return ctx.Err()
+			}
+		})
+		app.After = func() {
+			afterCalled = true
+		}
+
+		err := app.Run([]string{"app", "c"})
+		require.Equal(t, context.Canceled, err)
+		require.True(t, afterCalled, "after should have been called")
+	}
+}
+
+func TestHandleSignalsExitsOnSecondSignal(t *testing.T) {
+	cases := []struct {
+		exitCode int
+		expected int
+	}{
+		{0, 130},
+		{3, 3},
+	}
+
+	for _, cas := range cases {
+		exited := make(chan int, 1)
+		oldExiter := exiter
+		exiter = func(code int) {
+			exited <- code
+		}
+
+		app := App("app", "")
+		app.HandleSignals = true
+		app.SignalExitCode = cas.exitCode
+		app.Action = func() {
+			sendSignal(t, syscall.SIGINT)
+			waitForCancellation(t, app.Context())
+			sendSignal(t, syscall.SIGINT)
+
+			select {
+			case code := <-exited:
+				require.Equal(t, cas.expected, code)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("exit should have been called")
+			}
+		}
+
+		require.NoError(t,
+			app.Run([]string{"app"}))
+		exiter = oldExiter
+	}
+}
+
+func TestContextWithoutHandleSignals(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	app := App("app", "")
+	app.Command("c", "", func(cmd *Cmd) {
+		cmd.Action = func() {
+			require.Equal(t, ctx, cmd.Context())
+		}
+	})
+
+	require.NoError(t,
+		app.RunContext(ctx, []string{"app", "c"}))
+	require.Equal(t, context.Background(), App("app", "").Context())
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/jawher/mow.cli/internal/container"
+)
+
+/*
+SourceKind tells where the value of an option or an argument came from
+*/
+type SourceKind int
+
+const (
+	// SourceDefault means the value is the one the option or argument was declared with
+	SourceDefault SourceKind = iota
+	// SourceEnv means the value was read from an env var
+	SourceEnv
+	// SourceConfigFile means the value was read from the config file
+	SourceConfigFile
+	// SourceCommandLine means the value was provided in the call arguments
+	SourceCommandLine
+)
+
+func (k SourceKind) String() string {
+	switch k {
+	case SourceEnv:
+		return "env"
+	case SourceConfigFile:
+		return "config file"
+	case SourceCommandLine:
+		return "command line"
+	default:
+		return "default"
+	}
+}
+
+/*
+Source describes where the value of an option or an argument came from
+*/
+type Source struct {
+	Kind SourceKind
+	// The env var the value was read from, when Kind is SourceEnv
+	EnvVar string
+	// The path of the config file the value was read from, when Kind is SourceConfigFile
+	ConfigPath string
+	// The key of the value in the config file, e.g. remote.add.verbose, when Kind is SourceConfigFile
+	ConfigKey string
+	// The index in the call arguments of the last value of the option or argument, when Kind is SourceCommandLine, or -1 if unknown
+	Index int
+}
+
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceEnv:
+		return fmt.Sprintf("env $%s", s.EnvVar)
+	case SourceConfigFile:
+		return fmt.Sprintf("config file %s (%s)", s.ConfigPath, s.ConfigKey)
+	case SourceCommandLine:
+		return fmt.Sprintf("command line (argument %d)", s.Index)
+	default:
+		return s.Kind.String()
+	}
+}
+
+/*
+SourceOf returns where the value of the option or argument named name came from.
+
+name is either an option name, with or without the dashes (e.g. `v`, `verbose` or `--verbose`), or an argument name (e.g. `SRC`).
+It should be called after the call arguments were parsed, e.g. in an Action, and panics if the command has no such option or argument.
+*/
+func (c *Cmd) SourceOf(name string) Source {
+	con := c.lookupParam(name)
+	if con == nil {
+		panic(fmt.Sprintf("unknown option or argument %q", name))
+	}
+
+	switch {
+	case con.ValueSetFromArgs:
+		index := -1
+		if n := len(con.ValuePos); n > 0 && con.ValuePos[n-1] >= 0 {
+			index = c.argIndex(c.offset, con.ValuePos[n-1])
+		}
+		return Source{Kind: SourceCommandLine, Index: index}
+	case con.ValueSetFromEnv:
+		return Source{Kind: SourceEnv, EnvVar: con.ValueEnvVar}
+	case con.ValueSetFromConfig:
+		return Source{Kind: SourceConfigFile, ConfigPath: c.config.path, ConfigKey: con.ValueConfigKey}
+	default:
+		return Source{Kind: SourceDefault}
+	}
+}
+
+// lookupParam returns the option or argument named name, or nil if the command has none
+func (c *Cmd) lookupParam(name string) *container.Container {
+	if con, found := c.argsIdx[name]; found {
+		return con
+	}
+	if con, found := c.optionsIdx[name]; found {
+		return con
+	}
+	for _, n := range mkOptStrs(name) {
+		if con, found := c.optionsIdx[n]; found {
+			return con
+		}
+	}
+	return nil
+}
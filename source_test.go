@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceOf(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+	defer setAndRestoreEnv(map[string]string{"APP_NAME": "", "APP_TITLE": "from-env"})()
+
+	app := App("app", "")
+	app.Spec = "[-n] [-v] [--missing] [-t...]"
+	app.String(StringOpt{Name: "n name", EnvVar: "APP_NAME APP_TITLE"})
+	app.BoolOpt("v verbose", false, "")
+	app.StringOpt("missing", "", "")
+	app.StringsOpt("t tags", nil, "")
+	require.NoError(t, app.LoadConfig("testdata/config.json", nil))
+
+	var sources map[string]Source
+	app.Command("remote", "", func(cmd *Cmd) {
+		cmd.Command("add", "", func(cmd *Cmd) {
+			cmd.Spec = "[-f] [--url] [PORT]"
+			cmd.BoolOpt("f force", false, "")
+			cmd.StringOpt("url", "", "")
+			cmd.IntArg("PORT", 0, "")
+			cmd.Action = func() {
+				sources = map[string]Source{}
+				for _, name := range []string{"f", "url", "PORT"} {
+					sources[name] = cmd.SourceOf(name)
+				}
+			}
+		})
+	})
+	app.Action = func() {
+		sources = map[string]Source{}
+		for _, name := range []string{"name", "v", "--verbose", "missing", "t"} {
+			sources[name] = app.SourceOf(name)
+		}
+	}
+
+	require.NoError(t, app.Run([]string{"app", "-v", "-t", "a", "-t", "b"}))
+	require.Equal(t, map[string]Source{
+		"name":      {Kind: SourceEnv, EnvVar: "APP_TITLE"},
+		"v":         {Kind: SourceCommandLine, Index: 1},
+		"--verbose": {Kind: SourceCommandLine, Index: 1},
+		"missing":   {Kind: SourceDefault},
+		"t":         {Kind: SourceCommandLine, Index: 5},
+	}, sources)
+
+	require.NoError(t, app.Run([]string{"app", "remote", "add"}))
+	require.Equal(t, map[string]Source{
+		"f":    {Kind: SourceConfigFile, ConfigPath: "testdata/config.json", ConfigKey: "remote.add.force"},
+		"url":  {Kind: SourceConfigFile, ConfigPath: "testdata/config.json", ConfigKey: "remote.add.url"},
+		"PORT": {Kind: SourceConfigFile, ConfigPath: "testdata/config.json", ConfigKey: "remote.add.port"},
+	}, sources)
+
+	require.NoError(t, app.Run([]string{"app", "remote", "add", "-f", "--url", "x", "8000"}))
+	require.Equal(t, map[string]Source{
+		"f":    {Kind: SourceCommandLine, Index: 3},
+		"url":  {Kind: SourceCommandLine, Index: 5},
+		"PORT": {Kind: SourceCommandLine, Index: 6},
+	}, sources)
+}
+
+func TestSourceOfGeneratedSpec(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	app := App("app", "")
+	app.BoolOpt("q quiet", false, "")
+	app.BoolOpt("v verbose", false, "")
+	app.StringOpt("n name", "", "")
+	app.StringsOpt("t tags", nil, "")
+	app.StringArg("SRC", "", "")
+
+	var sources map[string]Source
+	app.Action = func() {
+		sources = map[string]Source{}
+		for _, name := range []string{"q", "v", "n", "t", "SRC"} {
+			sources[name] = app.SourceOf(name)
+		}
+	}
+
+	require.NoError(t, app.Run([]string{"app", "-q", "-t", "a", "-v", "-n", "x", "--tags=b", "a"}))
+	require.Equal(t, map[string]Source{
+		"q":   {Kind: SourceCommandLine, Index: 1},
+		"v":   {Kind: SourceCommandLine, Index: 4},
+		"n":   {Kind: SourceCommandLine, Index: 6},
+		"t":   {Kind: SourceCommandLine, Index: 7},
+		"SRC": {Kind: SourceCommandLine, Index: 8},
+	}, sources)
+
+	require.NoError(t, app.Run([]string{"app", "-qvnx", "a"}))
+	require.Equal(t, SourceCommandLine, sources["q"].Kind)
+	require.Equal(t, 1, sources["q"].Index)
+	require.Equal(t, 1, sources["v"].Index)
+	require.Equal(t, 1, sources["n"].Index)
+	require.Equal(t, 2, sources["SRC"].Index)
+}
+
+func TestSourceOfUnknownName(t *testing.T) {
+	app := App("app", "")
+	app.BoolOpt("v verbose", false, "")
+
+	require.Panics(t, func() {
+		app.SourceOf("x")
+	})
+}
+
+func TestSourceString(t *testing.T) {
+	cases := []struct {
+		source   Source
+		expected string
+	}{
+		{Source{}, "default"},
+		{Source{Kind: SourceEnv, EnvVar: "NAME"}, "env $NAME"},
+		{Source{Kind: SourceConfigFile, ConfigPath: "app.json", ConfigKey: "remote.add.force"}, "config file app.json (remote.add.force)"},
+		{Source{Kind: SourceCommandLine, Index: 3}, "command line (argument 3)"},
+	}
+
+	for _, cas := range cases {
+		t.Run(fmt.Sprintf("%#v", cas.source), func(t *testing.T) {
+			require.Equal(t, cas.expected, cas.source.String())
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jawher/mow.cli/internal/fsm"
+	"github.com/jawher/mow.cli/internal/matcher"
+	"github.com/jawher/mow.cli/internal/values"
+)
+
+const defaultSuggestionsDistance = 2
+
+// printSuggestions prints a "Did you mean ...?" line for every unknown option in args,
+// and for the unknown command at commandPos if it is not negative
+func (c *Cmd) printSuggestions(args []string, commandPos int) {
+	if c.DisableSuggestions {
+		return
+	}
+	for _, hint := range c.suggestions(args, commandPos) {
+		fmt.Fprintln(stdErr, hint)
+	}
+}
+
+// commandPos returns the position in the command args of the arg rejected by the fsm where a sub command was expected, -1 otherwise
+func (c *Cmd) commandPos(err error, args []string) int {
+	e, ok := err.(*fsm.ParseError)
+	if !ok || !e.Terminal || e.Pos >= len(args) || !c.hasVisibleCommands() {
+		return -1
+	}
+	return e.Pos
+}
+
+func (c *Cmd) suggestions(args []string, commandPos int) []string {
+	var res []string
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if c.pendingOption(args[:i]) != nil {
+			// arg is the value of the preceding option
+			continue
+		}
+
+		var candidates []string
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name := strings.SplitN(arg, "=", 2)[0]
+			if _, opt := matcher.LookupLongOpt(c.optionsIdx, name, c.abbreviations); opt != nil || len(c.abbreviationCandidates(name)) > 0 {
+				continue
+			}
+			candidates = c.similarOptions(name)
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			if c.isShortOptions(arg) {
+				continue
+			}
+			if len(arg) > 2 {
+				candidates = c.similarOptions(arg)
+			}
+			if len(candidates) == 0 {
+				if name := c.unknownOption(arg); name != "" {
+					arg = name
+					candidates = c.similarShortOptions(name)
+				}
+			}
+		case i == commandPos:
+			if len(c.abbreviationCandidates(arg)) > 0 {
+				continue
+			}
+			candidates = c.similarCommands(arg)
+		}
+
+		if len(candidates) > 0 {
+			res = append(res, fmt.Sprintf("Did you mean %s instead of %q?", formatCandidates(candidates), arg))
+		}
+	}
+	return res
+}
+
+// isShortOptions checks if arg is a valid sequence of folded short options, possibly ending with a value
+func (c *Cmd) isShortOptions(arg string) bool {
+	for i := 1; i < len(arg); i++ {
+		opt, found := c.optionsIdx["-"+arg[i:i+1]]
+		if !found {
+			return false
+		}
+		if !values.IsBool(opt.Value) {
+			return true
+		}
+	}
+	return true
+}
+
+// similarOptions returns the long option names close enough to the unknown option name
+func (c *Cmd) similarOptions(name string) []string {
+	var names []string
+	for _, n := range c.optionNames() {
+		if strings.HasPrefix(n, "--") {
+			names = append(names, n)
+		}
+	}
+	return c.similar(strings.TrimLeft(name, "-"), names, func(n string) string { return strings.TrimLeft(n, "-") })
+}
+
+// similarShortOptions returns the options close enough to the unknown short option name, e.g. -v for -V,
+// or --debug for -d if the --debug option has no short name
+func (c *Cmd) similarShortOptions(name string) []string {
+	letter := name[1:]
+	var res []string
+	for _, opt := range c.options {
+		short := ""
+		for _, n := range opt.Names {
+			if len(n) == 2 {
+				short = n
+				break
+			}
+		}
+		for _, n := range opt.Names {
+			switch {
+			case n == short && strings.EqualFold(n[1:], letter):
+				res = append(res, n)
+			case short == "" && strings.HasPrefix(n, "--") && strings.EqualFold(n[2:3], letter):
+				res = append(res, n)
+			}
+		}
+	}
+	return res
+}
+
+// similarCommands returns the aliases of the visible sub commands close enough to the unknown command name.
+// Only the sub commands with a close enough alias are initialized, to know if they are hidden,
+// and those which fail to initialize are skipped
+func (c *Cmd) similarCommands(name string) []string {
+	identity := func(n string) string { return n }
+	var aliases []string
+	for _, sub := range c.commands {
+		if sub.Hidden || len(c.similar(name, sub.aliases, identity)) == 0 {
+			continue
+		}
+		if err := sub.doInit(); err != nil || sub.Hidden {
+			continue
+		}
+		aliases = append(aliases, sub.aliases...)
+	}
+	return c.similar(name, aliases, identity)
+}
+
+func (c *Cmd) similar(name string, candidates []string, key func(string) string) []string {
+	maxDistance := c.SuggestionsDistance
+	if maxDistance <= 0 {
+		maxDistance = defaultSuggestionsDistance
+	}
+
+	type scored struct {
+		name     string
+		distance int
+	}
+	var matches []scored
+	for _, candidate := range candidates {
+		k := key(candidate)
+		d := editDistance(strings.ToLower(name), strings.ToLower(k))
+		if d <= maxDistance && d < len(k) {
+			matches = append(matches, scored{candidate, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+
+	var res []string
+	for _, m := range matches {
+		res = append(res, m.name)
+	}
+	return res
+}
+
+func formatCandidates(candidates []string) string {
+	quoted := make([]string, len(candidates))
+	for i, c := range candidates {
+		quoted[i] = fmt.Sprintf("%q", c)
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
+// editDistance returns the number of single character insertions, deletions, substitutions
+// and transpositions of two adjacent characters needed to turn a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = d[i-1][j-1] + cost
+			if d[i-1][j]+1 < d[i][j] {
+				d[i][j] = d[i-1][j] + 1
+			}
+			if d[i][j-1]+1 < d[i][j] {
+				d[i][j] = d[i][j-1] + 1
+			}
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
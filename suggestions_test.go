@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestions(t *testing.T) {
+	cases := []struct {
+		args     []string
+		distance int
+		disable  bool
+		expected []string
+	}{
+		{args: []string{"app", "stauts"}, expected: []string{`Did you mean "status" instead of "stauts"?`}},
+		{args: []string{"app", "STATUS2"}, expected: []string{`Did you mean "status" instead of "STATUS2"?`}},
+		{args: []string{"app", "stas"}, expected: []string{`Did you mean "stash" or "status" instead of "stas"?`}},
+		{args: []string{"app", "remot"}, expected: []string{`Did you mean "remote" instead of "remot"?`}},
+		{args: []string{"app", "secrt"}, expected: nil},
+		{args: []string{"app", "xyz"}, expected: nil},
+		{args: []string{"app", "--verbsoe"}, expected: []string{`Did you mean "--verbose" instead of "--verbsoe"?`}},
+		{args: []string{"app", "--verbsoe=true"}, expected: []string{`Did you mean "--verbose" instead of "--verbsoe=true"?`}},
+		{args: []string{"app", "-verbose"}, expected: []string{`Did you mean "--verbose" instead of "-verbose"?`}},
+		{args: []string{"app", "-x"}, expected: nil},
+		{args: []string{"app", "-V"}, expected: []string{`Did you mean "-v" instead of "-V"?`}},
+		{args: []string{"app", "-vN"}, expected: []string{`Did you mean "-n" instead of "-N"?`}},
+		{args: []string{"app", "-D"}, expected: []string{`Did you mean "--debug" instead of "-D"?`}},
+		{args: []string{"app", "--name", "stauts", "--verbsoe"}, expected: []string{`Did you mean "--verbose" instead of "--verbsoe"?`}},
+		{args: []string{"app", "-vnstauts", "--verbsoe"}, expected: []string{`Did you mean "--verbose" instead of "--verbsoe"?`}},
+		{args: []string{"app", "--", "stauts"}, expected: nil},
+		{args: []string{"app", "--verbsoe", "stauts"}, expected: []string{`Did you mean "--verbose" instead of "--verbsoe"?`}},
+		{args: []string{"app", "-v", "stauts"}, expected: []string{`Did you mean "status" instead of "stauts"?`}},
+		{args: []string{"app", "stsh"}, distance: 1, expected: []string{`Did you mean "stash" instead of "stsh"?`}},
+		{args: []string{"app", "stas"}, distance: 1, expected: []string{`Did you mean "stash" instead of "stas"?`}},
+		{args: []string{"app", "stauts"}, disable: true, expected: nil},
+		{args: []string{"app", "remote", "ad"}, expected: []string{`Did you mean "add" instead of "ad"?`}},
+		{args: []string{"app", "remote", "ad"}, disable: true, expected: nil},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q %d %v", cas.args, cas.distance, cas.disable), func(t *testing.T) {
+			var stdErr string
+			defer captureAndRestoreOutput(nil, &stdErr)()
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+			app.DisableSuggestions = cas.disable
+			app.SuggestionsDistance = cas.distance
+			app.BoolOpt("v verbose", false, "")
+			app.StringOpt("n name", "", "")
+			app.BoolOpt("debug", false, "")
+
+			app.Command("status", "", func(cmd *Cmd) {})
+			app.Command("stash", "", func(cmd *Cmd) {})
+			app.Command("remote", "", func(cmd *Cmd) {
+				cmd.Command("add", "", func(cmd *Cmd) {})
+			})
+			app.Command("secret", "", func(cmd *Cmd) {
+				cmd.Hidden = true
+			})
+
+			require.Error(t, app.Run(cas.args))
+
+			var hints []string
+			for _, line := range strings.Split(stdErr, "\n") {
+				if strings.HasPrefix(line, "Did you mean") {
+					hints = append(hints, line)
+				}
+			}
+			require.Equal(t, cas.expected, hints)
+		})
+	}
+}
+
+func TestSuggestionsOnlyForCommandPosition(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected []string
+	}{
+		{args: []string{"app", "remote", "origin", "ad"}, expected: []string{`Did you mean "add" instead of "ad"?`}},
+		{args: []string{"app", "remote", "ad", "x"}, expected: nil},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			var stdErr string
+			defer captureAndRestoreOutput(nil, &stdErr)()
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+			app.Command("remote", "", func(cmd *Cmd) {
+				cmd.Spec = "[NAME]"
+				cmd.StringArg("NAME", "", "")
+				cmd.Command("add", "", func(cmd *Cmd) {})
+			})
+
+			require.Error(t, app.Run(cas.args))
+
+			var hints []string
+			for _, line := range strings.Split(stdErr, "\n") {
+				if strings.HasPrefix(line, "Did you mean") {
+					hints = append(hints, line)
+				}
+			}
+			require.Equal(t, cas.expected, hints)
+		})
+	}
+}
+
+func TestSuggestionsOnlyInitializeSimilarCommands(t *testing.T) {
+	app := App("app", "")
+	inits := map[string]int{}
+	app.Command("status", "", func(cmd *Cmd) {
+		inits["status"]++
+	})
+	app.Command("broken", "", func(cmd *Cmd) {
+		inits["broken"]++
+		cmd.Spec = "[SRC"
+	})
+
+	require.True(t, app.hasVisibleCommands())
+	require.Empty(t, inits)
+
+	require.Nil(t, app.similarCommands("zzz"))
+	require.Empty(t, inits)
+
+	require.Nil(t, app.similarCommands("brokn"))
+	require.Equal(t, map[string]int{"broken": 1}, inits)
+
+	require.Equal(t, []string{"status"}, app.similarCommands("stauts"))
+	require.Equal(t, map[string]int{"broken": 1, "status": 1}, inits)
+}
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"status", "status", 0},
+		{"stauts", "status", 1},
+		{"ab", "ba", 1},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+	}
+
+	for _, cas := range cases {
+		require.Equal(t, cas.expected, editDistance(cas.a, cas.b), "editDistance(%q, %q)", cas.a, cas.b)
+	}
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppWithDurationOption(t *testing.T) {
+	cases := []struct {
+		args             []string
+		expectedOptValue time.Duration
+	}{
+		{[]string{"app"}, time.Minute},
+		{[]string{"app", "-o", "10s"}, 10 * time.Second},
+		{[]string{"app", "-o=1h30m"}, 90 * time.Minute},
+		{[]string{"app", "--option", "10ms"}, 10 * time.Millisecond},
+		{[]string{"app", "--option=2m"}, 2 * time.Minute},
+	}
+
+	for _, cas := range cases {
+		runAppAndCheckValue(t, "short", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.DurationOpt("o option", time.Minute, "")
+		})
+		runAppAndCheckValue(t, "struct", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.Duration(DurationOpt{
+				Name:  "o option",
+				Value: time.Minute,
+			})
+		})
+		runAppAndCheckValue(t, "short-ptr", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			var val time.Duration
+			app.DurationOptPtr(&val, "o option", time.Minute, "")
+			return &val
+		})
+		runAppAndCheckValue(t, "struct-ptr", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			var val time.Duration
+			app.DurationPtr(&val, DurationOpt{
+				Name:  "o option",
+				Value: time.Minute,
+			})
+			return &val
+		})
+	}
+}
+
+func TestAppWithDurationsOption(t *testing.T) {
+	cases := []struct {
+		args             []string
+		expectedOptValue []time.Duration
+	}{
+		{[]string{"app"}, []time.Duration{time.Second, time.Minute}},
+		{[]string{"app", "-o", "10s"}, []time.Duration{10 * time.Second}},
+		{[]string{"app", "-o", "10s", "--option=1h"}, []time.Duration{10 * time.Second, time.Hour}},
+	}
+
+	for _, cas := range cases {
+		runAppAndCheckValue(t, "short", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.DurationsOpt("o option", []time.Duration{time.Second, time.Minute}, "")
+		})
+		runAppAndCheckValue(t, "struct", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.Durations(DurationsOpt{
+				Name:  "o option",
+				Value: []time.Duration{time.Second, time.Minute},
+			})
+		})
+		runAppAndCheckValue(t, "short-ptr", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			var val []time.Duration
+			app.DurationsOptPtr(&val, "o option", []time.Duration{time.Second, time.Minute}, "")
+			return &val
+		})
+		runAppAndCheckValue(t, "struct-ptr", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			var val []time.Duration
+			app.DurationsPtr(&val, DurationsOpt{
+				Name:  "o option",
+				Value: []time.Duration{time.Second, time.Minute},
+			})
+			return &val
+		})
+	}
+}
+
+func TestAppWithTimeOption(t *testing.T) {
+	def := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		args             []string
+		expectedOptValue time.Time
+	}{
+		{[]string{"app"}, def},
+		{[]string{"app", "-o", "2021-05-06T07:08:09Z"}, time.Date(2021, 5, 6, 7, 8, 9, 0, time.UTC)},
+		{[]string{"app", "--option=2021-05-06T07:08:09Z"}, time.Date(2021, 5, 6, 7, 8, 9, 0, time.UTC)},
+	}
+
+	for _, cas := range cases {
+		runAppAndCheckValue(t, "short", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.TimeOpt("o option", def, "")
+		})
+		runAppAndCheckValue(t, "struct", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.Time(TimeOpt{
+				Name:  "o option",
+				Value: def,
+			})
+		})
+		runAppAndCheckValue(t, "short-ptr", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			var val time.Time
+			app.TimeOptPtr(&val, "o option", def, "")
+			return &val
+		})
+		runAppAndCheckValue(t, "struct-ptr", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			var val time.Time
+			app.TimePtr(&val, TimeOpt{
+				Name:  "o option",
+				Value: def,
+			})
+			return &val
+		})
+	}
+}
+
+func TestAppWithTimeOptionLayout(t *testing.T) {
+	runAppAndCheckValue(t, "layout", []string{"app", "--since", "2021-05-06"}, time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC), func(app *Cli) interface{} {
+		return app.Time(TimeOpt{
+			Name:   "since",
+			Layout: "2006-01-02",
+		})
+	})
+}
+
+func TestAppWithTimesOption(t *testing.T) {
+	cases := []struct {
+		args             []string
+		expectedOptValue []time.Time
+	}{
+		{[]string{"app"}, nil},
+		{[]string{"app", "-o", "2021-05-06"}, []time.Time{time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC)}},
+		{[]string{"app", "-o", "2021-05-06", "-o=2022-01-01"}, []time.Time{
+			time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC),
+			time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, cas := range cases {
+		runAppAndCheckValue(t, "struct", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			return app.Times(TimesOpt{
+				Name:   "o option",
+				Layout: "2006-01-02",
+			})
+		})
+		runAppAndCheckValue(t, "struct-ptr", cas.args, cas.expectedOptValue, func(app *Cli) interface{} {
+			var val []time.Time
+			app.TimesPtr(&val, TimesOpt{
+				Name:   "o option",
+				Layout: "2006-01-02",
+			})
+			return &val
+		})
+	}
+}
+
+func TestAppWithDurationArgs(t *testing.T) {
+	runAppAndCheckValue(t, "duration", []string{"app", "5s"}, 5*time.Second, func(app *Cli) interface{} {
+		return app.DurationArg("ARG", 0, "")
+	})
+	runAppAndCheckValue(t, "duration-ptr", []string{"app", "5s"}, 5*time.Second, func(app *Cli) interface{} {
+		var val time.Duration
+		app.DurationArgPtr(&val, "ARG", 0, "")
+		return &val
+	})
+	runAppAndCheckValue(t, "durations", []string{"app", "5s", "1m"}, []time.Duration{5 * time.Second, time.Minute}, func(app *Cli) interface{} {
+		app.Spec = "ARG..."
+		return app.Durations(DurationsArg{Name: "ARG"})
+	})
+}
+
+func TestAppWithTimeArgs(t *testing.T) {
+	runAppAndCheckValue(t, "time", []string{"app", "2021-05-06T07:08:09Z"}, time.Date(2021, 5, 6, 7, 8, 9, 0, time.UTC), func(app *Cli) interface{} {
+		return app.TimeArg("ARG", time.Time{}, "")
+	})
+	runAppAndCheckValue(t, "time-layout", []string{"app", "06/05/2021"}, time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC), func(app *Cli) interface{} {
+		return app.Time(TimeArg{Name: "ARG", Layout: "02/01/2006"})
+	})
+	runAppAndCheckValue(t, "times", []string{"app", "2021-05-06", "2022-01-01"}, []time.Time{
+		time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, func(app *Cli) interface{} {
+		app.Spec = "ARG..."
+		return app.Times(TimesArg{Name: "ARG", Layout: "2006-01-02"})
+	})
+}
+
+func TestTimeValuesFromEnv(t *testing.T) {
+	defer setAndRestoreEnv(map[string]string{
+		"TIMEOUT":   "30s",
+		"RETRIES":   "1s, 2s,4s",
+		"DEADLINES": "2021-05-06,2022-01-01",
+	})()
+
+	app := App("app", "")
+	timeout := app.Duration(DurationOpt{Name: "timeout", EnvVar: "TIMEOUT"})
+	retries := app.Durations(DurationsOpt{Name: "retry", EnvVar: "RETRIES"})
+	deadlines := app.Times(TimesOpt{Name: "deadline", EnvVar: "DEADLINES", Layout: "2006-01-02"})
+
+	called := false
+	app.Action = func() {
+		called = true
+		require.Equal(t, 30*time.Second, *timeout)
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, *retries)
+		require.Equal(t, []time.Time{
+			time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC),
+			time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, *deadlines)
+	}
+
+	require.NoError(t,
+		app.Run([]string{"app"}))
+	require.True(t, called)
+}
+
+func TestTimeValuesDefaultsInHelp(t *testing.T) {
+	var out string
+	defer captureAndRestoreOutput(nil, &out)()
+
+	app := App("app", "")
+	app.DurationOpt("timeout", 90*time.Second, "The timeout")
+	app.DurationOpt("zero", 0, "Not shown when zero")
+	app.Durations(DurationsOpt{Name: "retry", Value: []time.Duration{time.Second, time.Minute}, Desc: "The retries"})
+	app.Time(TimeOpt{Name: "since", Value: time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC), Layout: "2006-01-02", Desc: "The start"})
+	app.TimeOpt("until", time.Time{}, "Not shown when zero")
+
+	app.PrintHelp()
+
+	require.Contains(t, out, "The timeout (default 1m30s)")
+	require.Contains(t, out, "The retries (default [1s, 1m0s])")
+	require.Contains(t, out, "The start (default 2021-05-06)")
+	require.NotContains(t, out, "Not shown when zero (default")
+	require.NotContains(t, out, "0001")
+}
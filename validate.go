@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jawher/mow.cli/internal/container"
+	"github.com/jawher/mow.cli/internal/values"
+)
+
+// validate checks the parsed options and arguments: the values set from an env var or a config file,
+// the required options and constraints, and finally the command's own Validate function
+func (c *Cmd) validate() error {
+	if err := c.validateExternalValues(); err != nil {
+		return err
+	}
+	if err := c.checkConstraints(); err != nil {
+		return err
+	}
+	if c.Validate != nil {
+		return c.Validate()
+	}
+	return nil
+}
+
+// validateExternalValues runs the Validate functions of the options and arguments set from an env var or a config file.
+// The values set from the call arguments were already validated while parsing them
+func (c *Cmd) validateExternalValues() error {
+	for _, cons := range [][]*container.Container{c.options, c.args} {
+		for _, con := range cons {
+			if con.Validate == nil || !con.ValueSetExternally() {
+				continue
+			}
+			if err := con.Validate(); err != nil {
+				return &InvalidValueError{Command: c.path(), Index: -1, Name: containerName(con), Value: externalValue(con), Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// externalValue returns the value con was set to from an env var or a config file, masked if it is hidden
+func externalValue(con *container.Container) string {
+	if s, ok := con.Value.(*values.StringValue); ok {
+		return errorValue(con, string(*s))
+	}
+	return errorValue(con, con.Value.String())
+}
+
+// validateValue adapts the Validate function of an option or an argument, e.g. a func(int) error, into a function checking the value into points to,
+// or into itself for the flag.Value based ones
+func validateValue(validate, into interface{}) func() error {
+	if f := reflect.ValueOf(validate); !f.IsValid() || f.IsNil() {
+		return nil
+	}
+
+	switch f := validate.(type) {
+	case func(bool) error:
+		return func() error { return f(*into.(*bool)) }
+	case func(string) error:
+		return func() error { return f(*into.(*string)) }
+	case func(int) error:
+		return func() error { return f(*into.(*int)) }
+	case func(float64) error:
+		return func() error { return f(*into.(*float64)) }
+	case func([]string) error:
+		return func() error { return f(*into.(*[]string)) }
+	case func([]int) error:
+		return func() error { return f(*into.(*[]int)) }
+	case func([]float64) error:
+		return func() error { return f(*into.(*[]float64)) }
+	case func(time.Duration) error:
+		return func() error { return f(*into.(*time.Duration)) }
+	case func([]time.Duration) error:
+		return func() error { return f(*into.(*[]time.Duration)) }
+	case func(time.Time) error:
+		return func() error { return f(*into.(*time.Time)) }
+	case func([]time.Time) error:
+		return func() error { return f(*into.(*[]time.Time)) }
+	case func(map[string]string) error:
+		return func() error { return f(*into.(*map[string]string)) }
+	case func(map[string]int) error:
+		return func() error { return f(*into.(*map[string]int)) }
+	case func(flag.Value) error:
+		return func() error { return f(into.(flag.Value)) }
+	default:
+		panic(fmt.Sprintf("unsupported validate function type %T", validate))
+	}
+}
@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errPortRange = errors.New("must be between 1 and 65535")
+
+func validatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return errPortRange
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		args     []string
+		env      map[string]string
+		expected error
+	}{
+		{args: []string{"app"}},
+		{args: []string{"app", "--port", "8080", "host"}},
+		{args: []string{"app", "host"}, env: map[string]string{"PORT": "8080"}},
+		{
+			args:     []string{"app", "--port", "0"},
+			expected: &InvalidValueError{Command: "app", Index: 2, Name: "--port", Value: "0", Err: errPortRange},
+		},
+		{
+			args:     []string{"app", "--port=70000"},
+			expected: &InvalidValueError{Command: "app", Index: 1, Name: "--port", Value: "70000", Err: errPortRange},
+		},
+		{
+			args:     []string{"app"},
+			env:      map[string]string{"PORT": "0"},
+			expected: &InvalidValueError{Command: "app", Index: -1, Name: "--port", Value: "0", Err: errPortRange},
+		},
+		{
+			args:     []string{"app", "--port", "1", "-"},
+			expected: &InvalidValueError{Command: "app", Index: 3, Name: "HOST", Value: "-", Err: errors.New("invalid host")},
+		},
+		{
+			args:     []string{"app", "-t", "a", "-t", "b", "-t", "a"},
+			expected: &InvalidValueError{Command: "app", Index: 6, Name: "--tags", Value: "a", Err: errors.New("duplicate tags")},
+		},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q %v", cas.args, cas.env), func(t *testing.T) {
+			defer suppressOutput()()
+			defer setAndRestoreEnv(cas.env)()
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+			app.Spec = "[OPTIONS] [HOST]"
+			app.Int(IntOpt{Name: "p port", EnvVar: "PORT", Value: 80, Validate: validatePort})
+			app.Strings(StringsOpt{Name: "t tags", Validate: func(tags []string) error {
+				seen := map[string]bool{}
+				for _, tag := range tags {
+					if seen[tag] {
+						return errors.New("duplicate tags")
+					}
+					seen[tag] = true
+				}
+				return nil
+			}})
+			app.String(StringArg{Name: "HOST", Validate: func(host string) error {
+				if host == "-" {
+					return errors.New("invalid host")
+				}
+				return nil
+			}})
+
+			called := false
+			app.Action = func() {
+				called = true
+			}
+
+			err := app.Run(cas.args)
+			require.Equal(t, cas.expected, err)
+			require.Equal(t, cas.expected == nil, called)
+		})
+	}
+}
+
+func TestValidateAllTypes(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	cases := []struct {
+		config func(*Cli)
+		args   []string
+	}{
+		{
+			config: func(app *Cli) {
+				app.Bool(BoolOpt{Name: "o", Validate: func(bool) error { return errInvalid }})
+			},
+			args: []string{"app", "-o"},
+		},
+		{
+			config: func(app *Cli) {
+				app.String(StringOpt{Name: "o", Validate: func(string) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "x"},
+		},
+		{
+			config: func(app *Cli) {
+				app.Int(IntOpt{Name: "o", Validate: func(int) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "1"},
+		},
+		{
+			config: func(app *Cli) {
+				app.Float64(Float64Opt{Name: "o", Validate: func(float64) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "1"},
+		},
+		{
+			config: func(app *Cli) {
+				app.Strings(StringsOpt{Name: "o", Validate: func([]string) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "x"},
+		},
+		{
+			config: func(app *Cli) {
+				app.Ints(IntsOpt{Name: "o", Validate: func([]int) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "1"},
+		},
+		{
+			config: func(app *Cli) {
+				app.Floats64(Floats64Opt{Name: "o", Validate: func([]float64) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "1"},
+		},
+		{
+			config: func(app *Cli) {
+				app.Duration(DurationOpt{Name: "o", Validate: func(time.Duration) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "1s"},
+		},
+		{
+			config: func(app *Cli) {
+				app.Durations(DurationsOpt{Name: "o", Validate: func([]time.Duration) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "1s"},
+		},
+		{
+			config: func(app *Cli) {
+				app.Time(TimeOpt{Name: "o", Validate: func(time.Time) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "2020-01-01T00:00:00Z"},
+		},
+		{
+			config: func(app *Cli) {
+				app.Times(TimesOpt{Name: "o", Validate: func([]time.Time) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "2020-01-01T00:00:00Z"},
+		},
+		{
+			config: func(app *Cli) {
+				app.StringMap(StringMapOpt{Name: "o", Validate: func(map[string]string) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "a=b"},
+		},
+		{
+			config: func(app *Cli) {
+				app.IntMap(IntMapOpt{Name: "o", Validate: func(map[string]int) error { return errInvalid }})
+			},
+			args: []string{"app", "-o", "a=1"},
+		},
+		{
+			config: func(app *Cli) {
+				app.String(StringArg{Name: "ARG", Validate: func(string) error { return errInvalid }})
+			},
+			args: []string{"app", "x"},
+		},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			defer suppressOutput()()
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+			cas.config(app)
+			app.Action = func() {
+				t.Errorf("action should not have been called")
+			}
+
+			err := app.Run(cas.args)
+			require.True(t, errors.Is(err, errInvalid), "unexpected error %v", err)
+		})
+	}
+}
+
+func TestValidateDefaultValueIsNotValidated(t *testing.T) {
+	defer exitShouldNotCalled(t)()
+
+	app := App("app", "")
+	port := app.Int(IntOpt{Name: "port", Validate: validatePort})
+	app.Action = func() {}
+
+	require.NoError(t, app.Run([]string{"app"}))
+	require.Equal(t, 0, *port)
+}
+
+func TestValidateConfigValue(t *testing.T) {
+	defer suppressOutput()()
+
+	app := App("app", "")
+	app.ErrorHandling = flag.ContinueOnError
+	require.NoError(t, app.LoadConfig("testdata/config.json", nil))
+	app.Duration(DurationOpt{Name: "timeout", Validate: func(d time.Duration) error {
+		if d > 10*time.Second {
+			return errors.New("too long")
+		}
+		return nil
+	}})
+	app.Action = func() {}
+
+	err := app.Run([]string{"app"})
+	require.Equal(t, &InvalidValueError{Command: "app", Index: -1, Name: "--timeout", Value: "30s", Err: errors.New("too long")}, err)
+	require.EqualError(t, err, `invalid value "30s" for --timeout: too long`)
+}
+
+func TestValidateVar(t *testing.T) {
+	defer suppressOutput()()
+
+	app := App("app", "")
+	app.ErrorHandling = flag.ContinueOnError
+	app.Var(VarOpt{Name: "level", Value: &intValue{}, Validate: func(v flag.Value) error {
+		if n, _ := strconv.Atoi(v.String()); n > 3 {
+			return errors.New("at most 3")
+		}
+		return nil
+	}})
+	app.Action = func() {}
+
+	require.NoError(t, app.Run([]string{"app", "--level", "2"}))
+	require.EqualError(t, app.Run([]string{"app", "--level", "4"}), `invalid value "4" for --level: at most 3`)
+}
+
+type intValue struct {
+	n int
+}
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	v.n = n
+	return err
+}
+
+func (v *intValue) String() string {
+	return strconv.Itoa(v.n)
+}
+
+func TestCmdValidate(t *testing.T) {
+	errOrder := errors.New("--since must be before --until")
+
+	cases := []struct {
+		args     []string
+		expected error
+	}{
+		{args: []string{"app", "report", "--since", "1", "--until", "2"}},
+		{args: []string{"app", "report", "--since", "3", "--until", "2"}, expected: errOrder},
+	}
+
+	for _, cas := range cases {
+		cas := cas
+		t.Run(fmt.Sprintf("%q", cas.args), func(t *testing.T) {
+			var out, stdErr string
+			defer captureAndRestoreOutput(&out, &stdErr)()
+
+			app := App("app", "")
+			app.ErrorHandling = flag.ContinueOnError
+
+			var calls []string
+			app.Before = func() { calls = append(calls, "app.Before") }
+			app.After = func() { calls = append(calls, "app.After") }
+			app.Command("report", "", func(cmd *Cmd) {
+				cmd.ErrorHandling = flag.ContinueOnError
+				since := cmd.IntOpt("since", 0, "")
+				until := cmd.IntOpt("until", 0, "")
+				cmd.Validate = func() error {
+					calls = append(calls, "report.Validate")
+					if *since > *until {
+						return errOrder
+					}
+					return nil
+				}
+				cmd.Before = func() { calls = append(calls, "report.Before") }
+				cmd.Action = func() { calls = append(calls, "report.Action") }
+			})
+
+			err := app.Run(cas.args)
+			require.Equal(t, cas.expected, err)
+			if cas.expected != nil {
+				require.Equal(t, []string{"report.Validate"}, calls)
+				require.Contains(t, stdErr, "Error: --since must be before --until\n")
+				require.Contains(t, stdErr, "Usage: app report")
+				return
+			}
+			require.Equal(t, []string{"report.Validate", "app.Before", "report.Before", "report.Action", "app.After"}, calls)
+		})
+	}
+}
+
+func TestCmdValidateExitOnError(t *testing.T) {
+	defer suppressOutput()()
+
+	called := false
+	defer exitShouldBeCalledWith(t, 2, &called)()
+
+	app := App("app", "")
+	app.Validate = func() error {
+		return errors.New("invalid")
+	}
+	app.Action = func() {
+		t.Fatal("action should not have been called")
+	}
+
+	app.Run([]string{"app"})
+	require.True(t, called, "exit should have been called")
+}